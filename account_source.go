@@ -0,0 +1,41 @@
+package openbookdexgolang
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// AccountSource fetches account data, discriminator included, by address.
+type AccountSource interface {
+	GetAccounts(ctx context.Context, keys []solana.PublicKey) (map[solana.PublicKey][]byte, error)
+}
+
+// DirAccountSource reads accounts from a directory of dumps named
+// <address>.json, in any format accepted by LoadAccountFile. Files are read
+// again on every call so the directory can be updated in place.
+type DirAccountSource struct {
+	Dir string
+}
+
+func (s *DirAccountSource) GetAccounts(ctx context.Context, keys []solana.PublicKey) (map[solana.PublicKey][]byte, error) {
+	accounts := make(map[solana.PublicKey][]byte, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, data, err := LoadAccountFile(filepath.Join(s.Dir, key.String()+".json"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		accounts[key] = data
+	}
+	return accounts, nil
+}
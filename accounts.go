@@ -0,0 +1,135 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// accountDiscriminator returns the anchor discriminator of an account type.
+func accountDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("account:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+var (
+	marketDiscriminator    = accountDiscriminator("Market")
+	bookSideDiscriminator  = accountDiscriminator("BookSide")
+	eventHeapDiscriminator = accountDiscriminator("EventHeap")
+)
+
+func decodeAccount(discriminator [8]byte, data []byte, v interface{}) error {
+	if len(data) < len(discriminator) || !bytes.Equal(data[:len(discriminator)], discriminator[:]) {
+		return errors.New("invalid account discriminator")
+	}
+	return bin.NewBorshDecoder(data[len(discriminator):]).Decode(v)
+}
+
+// DecodeMarket decodes the data of a Market account, discriminator included.
+// Lot sizes and fees the program would never have created are rejected.
+func DecodeMarket(data []byte) (*Market, error) {
+	var market Market
+	if err := decodeAccount(marketDiscriminator, data, &market); err != nil {
+		return nil, err
+	}
+	if err := market.validate(); err != nil {
+		return nil, err
+	}
+	return &market, nil
+}
+
+// DecodeBookSide decodes the data of a BookSide account, discriminator included.
+func DecodeBookSide(data []byte) (*BookSide, error) {
+	var bookSide BookSide
+	if err := decodeAccount(bookSideDiscriminator, data, &bookSide); err != nil {
+		return nil, err
+	}
+	return &bookSide, nil
+}
+
+// DecodeEventHeap decodes the data of an EventHeap account, discriminator included.
+func DecodeEventHeap(data []byte) (*EventHeap, error) {
+	var eventHeap EventHeap
+	if err := decodeAccount(eventHeapDiscriminator, data, &eventHeap); err != nil {
+		return nil, err
+	}
+	return &eventHeap, nil
+}
+
+// Clock is the Clock sysvar account.
+type Clock struct {
+	Slot                uint64
+	EpochStartTimestamp int64
+	Epoch               uint64
+	LeaderScheduleEpoch uint64
+	UnixTimestamp       int64
+}
+
+// DecodeClock decodes the data of the Clock sysvar, which has no
+// discriminator.
+func DecodeClock(data []byte) (*Clock, error) {
+	var clock Clock
+	if err := bin.NewBinDecoder(data).Decode(&clock); err != nil {
+		return nil, err
+	}
+	return &clock, nil
+}
+
+// accountFile is the layout written by `solana account --output json`.
+type accountFile struct {
+	Pubkey  string `json:"pubkey"`
+	Account struct {
+		Data []string `json:"data"`
+	} `json:"account"`
+}
+
+// LoadAccountFile reads an account dump. Both the JSON written by
+// `solana account --output json` and a file holding only the base64 encoded
+// account data are accepted. The address is zero when the file has none.
+func LoadAccountFile(path string) (solana.PublicKey, []byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return solana.PublicKey{}, nil, err
+	}
+	return ParseAccountFile(content)
+}
+
+// ParseAccountFile parses the content of an account dump, see LoadAccountFile.
+func ParseAccountFile(content []byte) (solana.PublicKey, []byte, error) {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 || content[0] != '{' {
+		data, err := base64.StdEncoding.DecodeString(string(content))
+		return solana.PublicKey{}, data, err
+	}
+
+	var file accountFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return solana.PublicKey{}, nil, err
+	}
+
+	var address solana.PublicKey
+	if file.Pubkey != "" {
+		var err error
+		address, err = solana.PublicKeyFromBase58(file.Pubkey)
+		if err != nil {
+			return solana.PublicKey{}, nil, err
+		}
+	}
+
+	if len(file.Account.Data) != 2 || file.Account.Data[1] != "base64" {
+		return solana.PublicKey{}, nil, errors.New("account data must be base64 encoded")
+	}
+	data, err := base64.StdEncoding.DecodeString(file.Account.Data[0])
+	if err != nil {
+		return solana.PublicKey{}, nil, err
+	}
+	return address, data, nil
+}
@@ -0,0 +1,227 @@
+package openbookdexgolang
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// ArbitrageOpportunity is a cycle of trades starting and ending in Mint that
+// gives back more than it takes.
+type ArbitrageOpportunity struct {
+	Mint      solana.PublicKey
+	Legs      []RouteLeg
+	InAmount  uint64
+	OutAmount uint64
+	Profit    uint64
+}
+
+// ArbitrageDetector looks for profitable cycles over a fixed set of markets:
+// same-pair cycles buying on one market and selling on another, and
+// triangular cycles through three mints.
+type ArbitrageDetector struct {
+	router *Router
+}
+
+func NewArbitrageDetector(markets []*OpenBookMarket) *ArbitrageDetector {
+	return &ArbitrageDetector{router: NewRouter(markets)}
+}
+
+// Detect returns the opportunities of every cycle starting in one of the given
+// mints, or in any mint when none are given. Then the same cycle is reported
+// once for each of its mints.
+//
+// A cycle is only sized when the product of its legs' top of book rates, after
+// taker fees, is above one. It is then quoted leg by leg for the cumulative
+// amounts taking each order of every leg, and the most profitable size is
+// kept. Whatever a leg leaves unfilled is counted as lost.
+func (d *ArbitrageDetector) Detect(startMints ...solana.PublicKey) ([]ArbitrageOpportunity, error) {
+	if len(startMints) == 0 {
+		for mint := range d.router.edges {
+			startMints = append(startMints, mint)
+		}
+	}
+
+	opportunities := make([]ArbitrageOpportunity, 0)
+	for _, start := range startMints {
+		for _, first := range d.router.edges[start] {
+			for _, second := range d.router.edges[first.outputMint] {
+				if second.market == first.market {
+					continue
+				}
+
+				// Same pair
+				if second.outputMint == start {
+					opportunity, err := d.sizeCycle(start, first, second)
+					if err != nil {
+						return nil, err
+					}
+					if opportunity != nil {
+						opportunities = append(opportunities, *opportunity)
+					}
+					continue
+				}
+
+				// Triangular
+				for _, third := range d.router.edges[second.outputMint] {
+					if third.outputMint != start || third.market == first.market || third.market == second.market {
+						continue
+					}
+					opportunity, err := d.sizeCycle(start, first, second, third)
+					if err != nil {
+						return nil, err
+					}
+					if opportunity != nil {
+						opportunities = append(opportunities, *opportunity)
+					}
+				}
+			}
+		}
+	}
+
+	return opportunities, nil
+}
+
+// sizeCycle returns the most profitable opportunity trading through the edges
+// in order, or nil when the cycle doesn't pay.
+//
+// The profit only changes slope where a leg finishes taking an order, so the
+// sizes tried are the first leg inputs at which any leg reaches the end of one
+// of its orders.
+func (d *ArbitrageDetector) sizeCycle(start solana.PublicKey, edges ...routeEdge) (*ArbitrageOpportunity, error) {
+	legSteps := make([][]splitStep, 0, len(edges))
+	rate := 1.0
+	inputMint := start
+	for i, edge := range edges {
+		steps, err := edge.market.splitSteps(i, inputMint)
+		if err != nil {
+			return nil, err
+		}
+		if len(steps) == 0 {
+			return nil, nil
+		}
+		legSteps = append(legSteps, steps)
+		rate *= steps[0].rate()
+		inputMint = edge.outputMint
+	}
+	if rate <= 1 {
+		return nil, nil
+	}
+
+	sizes := make([]uint64, 0)
+	var maxInAmount uint64
+	for _, step := range legSteps[0] {
+		maxInAmount += step.inAmount
+		sizes = append(sizes, maxInAmount)
+	}
+	for leg := 1; leg < len(edges); leg++ {
+		var legInAmount uint64
+		for _, step := range legSteps[leg] {
+			legInAmount += step.inAmount
+			size, err := cycleInputFor(start, edges[:leg], legInAmount, maxInAmount)
+			if err != nil {
+				return nil, err
+			}
+			if size == 0 {
+				// Later orders of the leg are out of reach too
+				break
+			}
+			sizes = append(sizes, size)
+		}
+	}
+
+	var best *ArbitrageOpportunity
+	tried := make(map[uint64]bool, len(sizes))
+	for _, inAmount := range sizes {
+		if tried[inAmount] {
+			continue
+		}
+		tried[inAmount] = true
+
+		opportunity, err := quoteCycle(start, inAmount, edges)
+		if err != nil {
+			return nil, err
+		}
+		if opportunity == nil {
+			continue
+		}
+		if best == nil || opportunity.Profit > best.Profit {
+			best = opportunity
+		}
+	}
+
+	return best, nil
+}
+
+// cycleInputFor returns the least input of start, at most maxInAmount, for
+// which trading through the edges gives at least amount, or 0 when none does.
+// Quotes never give less for more, so the input is bisected.
+func cycleInputFor(start solana.PublicKey, edges []routeEdge, amount, maxInAmount uint64) (uint64, error) {
+	out, err := cycleOutAmount(start, maxInAmount, edges)
+	if err != nil || out < amount {
+		return 0, err
+	}
+
+	low, high := uint64(1), maxInAmount
+	for low < high {
+		mid := low + (high-low)/2
+		out, err := cycleOutAmount(start, mid, edges)
+		if err != nil {
+			return 0, err
+		}
+		if out >= amount {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+// cycleOutAmount is the amount trading inAmount of start through the edges
+// gives, 0 when a leg fills nothing.
+func cycleOutAmount(start solana.PublicKey, inAmount uint64, edges []routeEdge) (uint64, error) {
+	amount := inAmount
+	inputMint := start
+	for _, edge := range edges {
+		leg, err := quoteLeg(edge, inputMint, amount)
+		if err != nil || leg == nil {
+			return 0, err
+		}
+		amount = leg.Quote.OutAmount
+		inputMint = edge.outputMint
+	}
+	return amount, nil
+}
+
+// quoteCycle quotes inAmount of start through the edges, feeding every leg
+// with the out amount of the previous one.
+func quoteCycle(start solana.PublicKey, inAmount uint64, edges []routeEdge) (*ArbitrageOpportunity, error) {
+	legs := make([]RouteLeg, 0, len(edges))
+	amount := inAmount
+	inputMint := start
+	for _, edge := range edges {
+		leg, err := quoteLeg(edge, inputMint, amount)
+		if err != nil {
+			return nil, err
+		}
+		if leg == nil {
+			return nil, nil
+		}
+		legs = append(legs, *leg)
+		amount = leg.Quote.OutAmount
+		inputMint = edge.outputMint
+	}
+
+	// The whole input is committed to the cycle, including what the first
+	// leg leaves unfilled
+	if amount <= inAmount {
+		return nil, nil
+	}
+
+	return &ArbitrageOpportunity{
+		Mint:      start,
+		Legs:      legs,
+		InAmount:  inAmount,
+		OutAmount: amount,
+		Profit:    amount - inAmount,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package openbookdexgolang
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func expectOpportunity(t *testing.T, opportunities []ArbitrageOpportunity, markets []solana.PublicKey, inAmount, profit uint64) {
+	t.Helper()
+	if len(opportunities) != 1 {
+		t.Fatalf("%d opportunities, expected 1", len(opportunities))
+	}
+	opportunity := opportunities[0]
+	if len(opportunity.Legs) != len(markets) {
+		t.Fatalf("%d legs, expected %d", len(opportunity.Legs), len(markets))
+	}
+	for i, leg := range opportunity.Legs {
+		if leg.Market != markets[i] {
+			t.Fatalf("leg %d through %s, expected %s", i, leg.Market, markets[i])
+		}
+	}
+	if opportunity.InAmount != inAmount || opportunity.Profit != profit || opportunity.OutAmount != inAmount+profit {
+		t.Fatalf("%d in for %d profit, expected %d for %d", opportunity.InAmount, opportunity.Profit, inAmount, profit)
+	}
+}
+
+func TestDetectSamePair(t *testing.T) {
+	// Buy base at 100 on one market and sell it at 110 on the other
+	buy := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(100, 10), ask(120, 10))
+	sell := newRouterTestMarket(t, 2, testBaseMint, testQuoteMint, bid(110, 10), bid(105, 10))
+
+	opportunities, err := NewArbitrageDetector([]*OpenBookMarket{buy, sell}).Detect(testQuoteMint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectOpportunity(t, opportunities, []solana.PublicKey{buy.key, sell.key}, 1_000, 100)
+
+	// Without a crossed book there is nothing to take
+	sell = newRouterTestMarket(t, 2, testBaseMint, testQuoteMint, bid(99, 10))
+	opportunities, err = NewArbitrageDetector([]*OpenBookMarket{buy, sell}).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opportunities) != 0 {
+		t.Fatalf("opportunities %+v without a crossed book", opportunities)
+	}
+}
+
+func TestDetectSizeLimitedByLaterLeg(t *testing.T) {
+	// All 10 base cost 1000 and sell for 3 * 110 + 7 * 90 = 960. Only the 3
+	// base the second market buys at 110 pay.
+	buy := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(100, 10))
+	sell := newRouterTestMarket(t, 2, testBaseMint, testQuoteMint, bid(110, 3), bid(90, 7))
+
+	opportunities, err := NewArbitrageDetector([]*OpenBookMarket{buy, sell}).Detect(testQuoteMint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectOpportunity(t, opportunities, []solana.PublicKey{buy.key, sell.key}, 300, 30)
+}
+
+func TestDetectTriangular(t *testing.T) {
+	// 1000 quote buy 10 base, which sell for 500 of the other mint, which
+	// sell for 1500 quote
+	baseQuote := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(100, 10))
+	baseOther := newRouterTestMarket(t, 2, testBaseMint, testOtherMint, bid(50, 10))
+	otherQuote := newRouterTestMarket(t, 3, testOtherMint, testQuoteMint, bid(3, 500))
+
+	detector := NewArbitrageDetector([]*OpenBookMarket{baseQuote, baseOther, otherQuote})
+	opportunities, err := detector.Detect(testQuoteMint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectOpportunity(t, opportunities, []solana.PublicKey{baseQuote.key, baseOther.key, otherQuote.key}, 1_000, 500)
+
+	// The same cycle starting from each of its mints
+	opportunities, err = detector.Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opportunities) != 3 {
+		t.Fatalf("%d opportunities, expected the cycle from each of its 3 mints", len(opportunities))
+	}
+}
@@ -50,7 +50,6 @@ func AmountsFromBook(
 	oraclePrice *big.Float,
 	nowTs uint64,
 ) (Amounts, error) {
-
 	// Handle the optional oracle price logic
 	var oraclePriceLots *int64
 	if oraclePrice != nil {
@@ -61,11 +60,25 @@ func AmountsFromBook(
 		oraclePriceLots = &priceLot
 	}
 
+	amounts, _, err := amountsFromBook(book, side, maxBaseLots, maxQuoteLotsIncludingFees, market, oraclePriceLots, nowTs, TakeOptions{})
+	return amounts, err
+}
+
+func amountsFromBook(
+	book Orderbook,
+	side Side,
+	maxBaseLots int64,
+	maxQuoteLotsIncludingFees int64,
+	market *Market,
+	oraclePriceLots *int64,
+	nowTs uint64,
+	options TakeOptions,
+) (Amounts, TakeSimulation, error) {
 	// Placeholder for accounts array, if needed
 	accounts := make([]solana.PublicKey, 0) // Adjust based on what the accounts array should hold
 
 	// Call iterateBook, simulating the book iteration logic
-	totalBaseLotsTaken, totalQuoteLotsTaken, makersRebates, notEnoughLiquidity := IterateBook(
+	take, err := iterateBook(
 		book,
 		side,
 		maxBaseLots,
@@ -74,19 +87,23 @@ func AmountsFromBook(
 		oraclePriceLots,
 		nowTs,
 		&accounts,
+		options,
 	)
+	if err != nil {
+		return Amounts{}, TakeSimulation{}, err
+	}
 
 	// Calculate total_base_taken_native and total_quote_taken_native
-	totalBaseTakenNative := uint64(totalBaseLotsTaken * market.BaseLotSize)
-	totalQuoteTakenNative := uint64(totalQuoteLotsTaken * market.QuoteLotSize)
+	totalBaseTakenNative := uint64(take.TotalBaseLotsTaken * market.BaseLotSize)
+	totalQuoteTakenNative := uint64(take.TotalQuoteLotsTaken * market.QuoteLotSize)
 
 	// Return the calculated Amounts struct
 	return Amounts{
 		TotalBaseTakenNative:  totalBaseTakenNative,
 		TotalQuoteTakenNative: totalQuoteTakenNative,
-		Fee:                   uint64(makersRebates),
-		NotEnoughLiquidity:    notEnoughLiquidity,
-	}, nil
+		Fee:                   uint64(take.MakerRebates),
+		NotEnoughLiquidity:    take.NotEnoughLiquidity,
+	}, take, nil
 }
 
 func (s Side) InvertSide() Side {
@@ -106,6 +123,23 @@ func IterateBook(
 	nowTs uint64,
 	accounts *[]solana.PublicKey,
 ) (int64, int64, int64, bool) {
+	// Without a taker account there is nothing to self trade with, so this
+	// can't fail
+	take, _ := iterateBook(book, side, maxBaseLots, maxQuoteLots, market, oraclePriceLots, nowTs, accounts, TakeOptions{})
+	return take.TotalBaseLotsTaken, take.TotalQuoteLotsTaken, take.MakerRebates, take.NotEnoughLiquidity
+}
+
+func iterateBook(
+	book Orderbook,
+	side Side,
+	maxBaseLots int64,
+	maxQuoteLots int64,
+	market *Market,
+	oraclePriceLots *int64,
+	nowTs uint64,
+	accounts *[]solana.PublicKey,
+	options TakeOptions,
+) (TakeSimulation, error) {
 	var limit = MAXIMUM_TAKEN_ORDERS
 	var numberOfProcessedFillEvents = 0
 	var numberOfDroppedExpiredOrders = 0
@@ -118,28 +152,42 @@ func IterateBook(
 		orderMaxQuoteLots = maxQuoteLots
 	}
 
-	var makerRebatesAcc int64
+	// Running out of orders is the reason unless the loop stops earlier
+	take := TakeSimulation{StopReason: StopBookEmpty}
 	var remainingBaseLots = orderMaxBaseLots
 	var remainingQuoteLots = orderMaxQuoteLots
 	opposingBookSide := book.BookSide(side.InvertSide())
 	iter := opposingBookSide.IterAllIncludingInvalid(nowTs, oraclePriceLots)
-	for iter.Next() {
-		bestOpposing := iter.Item()
+	for bestOpposing, ok := iter.Next(); ok; bestOpposing, ok = iter.Next() {
+		// A filled take stops before dropping any more expired orders
+		if remainingBaseLots == 0 || remainingQuoteLots == 0 {
+			take.StopReason = filledStopReason(remainingBaseLots)
+			break
+		}
+
 		if !bestOpposing.IsValid() {
 			if numberOfDroppedExpiredOrders < DROP_EXPIRED_ORDER_LIMIT {
 				*accounts = append(*accounts, bestOpposing.Node.Owner)
 				numberOfDroppedExpiredOrders++
 			}
+			take.trace(options, MatchStepDropped, &bestOpposing, 0, 0, 0)
 			continue
 		}
 
-		if remainingBaseLots == 0 || remainingQuoteLots == 0 || limit == 0 {
+		bestOpposingPrice := bestOpposing.PriceLots
+		if options.LimitPriceLots != nil && !side.IsPriceWithinLimit(bestOpposingPrice, *options.LimitPriceLots) {
+			take.StopReason = StopPriceLimit
+			break
+		}
+
+		if limit == 0 {
+			take.StopReason = StopOrderLimit
 			break
 		}
 
-		bestOpposingPrice := bestOpposing.PriceLots
 		maxMatchByQuote := remainingQuoteLots / bestOpposingPrice
 		if maxMatchByQuote == 0 {
+			take.StopReason = StopQuoteExhausted
 			break
 		}
 
@@ -147,7 +195,25 @@ func IterateBook(
 		matchBaseLots = int64(math.Min(float64(matchBaseLots), float64(maxMatchByQuote)))
 		matchQuoteLots := matchBaseLots * bestOpposingPrice
 
-		makerRebatesAcc += int64(market.MakerRebateFloor(uint64(matchQuoteLots * market.QuoteLotSize)))
+		if selfTrade := options.SelfTrade; selfTrade != nil && bestOpposing.Node.Owner == selfTrade.OpenOrdersAccount {
+			switch selfTrade.Behavior {
+			case DecrementTake:
+				// Still matched, but the maker gets no rebate on it
+				take.SelfTrades = append(take.SelfTrades, newSelfTrade(&bestOpposing, matchBaseLots, false))
+				take.trace(options, MatchStepSelfTrade, &bestOpposing, matchBaseLots, matchQuoteLots, 0)
+			case CancelProvide:
+				// The resting order is cancelled and the taker moves on
+				take.SelfTrades = append(take.SelfTrades, newSelfTrade(&bestOpposing, bestOpposing.Node.Quantity, true))
+				take.trace(options, MatchStepCancelled, &bestOpposing, 0, 0, 0)
+				continue
+			default:
+				return TakeSimulation{}, ErrWouldSelfTrade
+			}
+		} else {
+			makerRebate := int64(market.MakerRebateFloor(uint64(matchQuoteLots * market.QuoteLotSize)))
+			take.MakerRebates += makerRebate
+			take.trace(options, MatchStepMatched, &bestOpposing, matchBaseLots, matchQuoteLots, makerRebate)
+		}
 
 		remainingBaseLots -= matchBaseLots
 		remainingQuoteLots -= matchQuoteLots
@@ -160,17 +226,21 @@ func IterateBook(
 		}
 	}
 
-	totalBaseLotsTaken := orderMaxBaseLots - remainingBaseLots
-	totalQuoteLotsTaken := orderMaxQuoteLots - remainingQuoteLots
+	// The last order can fill the take exactly
+	if take.StopReason == StopBookEmpty && (remainingBaseLots == 0 || remainingQuoteLots == 0) {
+		take.StopReason = filledStopReason(remainingBaseLots)
+	}
+
+	take.TotalBaseLotsTaken = orderMaxBaseLots - remainingBaseLots
+	take.TotalQuoteLotsTaken = orderMaxQuoteLots - remainingQuoteLots
 
-	notEnoughLiquidity := false
 	if side == Ask {
-		notEnoughLiquidity = remainingBaseLots != 0
+		take.NotEnoughLiquidity = remainingBaseLots != 0
 	} else {
-		notEnoughLiquidity = remainingQuoteLots != 0
+		take.NotEnoughLiquidity = remainingQuoteLots != 0
 	}
 
-	return totalBaseLotsTaken, totalQuoteLotsTaken, makerRebatesAcc, notEnoughLiquidity
+	return take, nil
 }
 
 func (s Side) IsPriceBetter(lhs int64, rhs int64) bool {
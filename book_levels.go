@@ -0,0 +1,84 @@
+package openbookdexgolang
+
+import "github.com/gagliardetto/solana-go"
+
+// L2Level is the aggregated size of all valid orders at one price.
+type L2Level struct {
+	PriceLots int64 `json:"priceLots"`
+	BaseLots  int64 `json:"baseLots"`
+	Orders    int   `json:"orders"`
+}
+
+// L3Order is a single resting order as seen by a taker.
+type L3Order struct {
+	Key           OrderId           `json:"key"`
+	OrderTree     BookSideOrderTree `json:"orderTree"`
+	PriceLots     int64             `json:"priceLots"`
+	Quantity      int64             `json:"quantity"`
+	Owner         solana.PublicKey  `json:"owner"`
+	OwnerSlot     uint8             `json:"ownerSlot"`
+	ClientOrderID uint64            `json:"clientOrderId"`
+	Timestamp     uint64            `json:"timestamp"`
+	TimeInForce   uint16            `json:"timeInForce"`
+	PegLimit      int64             `json:"pegLimit"`
+	State         OrderState        `json:"state"`
+}
+
+func (b *BookSide) Side() Side {
+	if b.Nodes.order_tree_type() == Bids {
+		return Bid
+	}
+	return Ask
+}
+
+// L2 aggregates the valid orders of the side by price, best price first. A
+// depth of 0 returns every level.
+func (b *BookSide) L2(nowTs uint64, oraclePriceLots *int64, depth int) []L2Level {
+	levels := make([]L2Level, 0)
+	iter := b.IterAllIncludingInvalid(nowTs, oraclePriceLots)
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		if !item.IsValid() {
+			continue
+		}
+
+		last := len(levels) - 1
+		if last >= 0 && levels[last].PriceLots == item.PriceLots {
+			levels[last].BaseLots += item.Node.Quantity
+			levels[last].Orders++
+			continue
+		}
+
+		if depth > 0 && len(levels) == depth {
+			break
+		}
+		levels = append(levels, L2Level{
+			PriceLots: item.PriceLots,
+			BaseLots:  item.Node.Quantity,
+			Orders:    1,
+		})
+	}
+	return levels
+}
+
+// L3 lists every order of the side in matching order, including expired and
+// peg limited ones, which are marked Invalid.
+func (b *BookSide) L3(nowTs uint64, oraclePriceLots *int64) []L3Order {
+	orders := make([]L3Order, 0)
+	iter := b.IterAllIncludingInvalid(nowTs, oraclePriceLots)
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		orders = append(orders, L3Order{
+			Key:           item.Node.OrderId(),
+			OrderTree:     item.Handle.OrderTree,
+			PriceLots:     item.PriceLots,
+			Quantity:      item.Node.Quantity,
+			Owner:         item.Node.Owner,
+			OwnerSlot:     item.Node.OwnerSlot,
+			ClientOrderID: item.Node.ClientOrderID,
+			Timestamp:     item.Node.Timestamp,
+			TimeInForce:   item.Node.TimeInForce,
+			PegLimit:      item.Node.PegLimit,
+			State:         item.State,
+		})
+	}
+	return orders
+}
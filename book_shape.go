@@ -0,0 +1,53 @@
+package openbookdexgolang
+
+// BookShape is the effective state of an Orderbook at a single oracle price.
+type BookShape struct {
+	OraclePriceLots int64
+	BestBidLots     *int64 // Option<i64> is represented as a pointer
+	BestAskLots     *int64 // Option<i64> is represented as a pointer
+	BidBaseLots     int64  // Total valid base lots resting on the bids
+	BidQuoteLots    int64  // Total valid quote lots resting on the bids
+	AskBaseLots     int64  // Total valid base lots resting on the asks
+	AskQuoteLots    int64  // Total valid quote lots resting on the asks
+}
+
+// AtOraclePrices re-prices the oracle pegged orders of both sides at each of
+// the given oracle prices (in lots) and returns the best prices and depth the
+// book would have at that price.
+//
+// The book itself is not copied: every oracle price walks the same BookSide
+// node arrays with a fresh iterator.
+func (o *Orderbook) AtOraclePrices(oraclePricesLots []int64, nowTs uint64) []BookShape {
+	shapes := make([]BookShape, 0, len(oraclePricesLots))
+	for _, oraclePriceLots := range oraclePricesLots {
+		shape := BookShape{OraclePriceLots: oraclePriceLots}
+		shape.BestBidLots, shape.BidBaseLots, shape.BidQuoteLots = bookSideShape(o.Bids, nowTs, oraclePriceLots)
+		shape.BestAskLots, shape.AskBaseLots, shape.AskQuoteLots = bookSideShape(o.Asks, nowTs, oraclePriceLots)
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+func bookSideShape(bookSide *BookSide, nowTs uint64, oraclePriceLots int64) (*int64, int64, int64) {
+	if bookSide == nil {
+		return nil, 0, 0
+	}
+
+	var bestPriceLots *int64
+	var baseLots, quoteLots int64
+	iter := bookSide.IterAllIncludingInvalid(nowTs, &oraclePriceLots)
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		// Expired and peg limited orders would be dropped by a taker, not matched
+		if !item.IsValid() {
+			continue
+		}
+
+		if bestPriceLots == nil {
+			price := item.PriceLots
+			bestPriceLots = &price
+		}
+		baseLots = saturatingAdd(baseLots, item.Node.Quantity)
+		quoteLots = saturatingAdd(quoteLots, saturatingMul(item.Node.Quantity, item.PriceLots))
+	}
+	return bestPriceLots, baseLots, quoteLots
+}
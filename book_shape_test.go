@@ -0,0 +1,71 @@
+package openbookdexgolang
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAtOraclePrices(t *testing.T) {
+	book, err := BuildOrderbook([]BookOrder{
+		bid(100, 2),
+		// Expired from timestamp 5 on
+		{Side: Bid, PriceLots: 95, Quantity: 3, Owner: testOwner, TimeInForce: 5},
+		// Invalid once the oracle price goes above 102
+		{Side: Bid, OraclePegged: true, PriceLots: -10, PegLimit: 92, Quantity: 4, Owner: testOwner},
+		ask(110, 1),
+		// Invalid while the oracle price is below 103
+		{Side: Ask, OraclePegged: true, PriceLots: 5, PegLimit: 108, Quantity: 2, Owner: testOwner},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type shape struct {
+		bestBid, bidBase, bidQuote int64
+		bestAsk, askBase, askQuote int64
+	}
+	expected := map[int64]shape{
+		// The pegged bid at 90 is second to the fixed one, the pegged ask at
+		// 105 is past its limit
+		100: {100, 6, 560, 110, 1, 110},
+		// The pegged bid at 95 is past its limit, the pegged ask joins the
+		// fixed one at 110
+		105: {100, 2, 200, 110, 3, 330},
+		// The pegged bid at -5 has no price and is skipped, the pegged ask at 10
+		// is past its limit
+		5: {100, 2, 200, 110, 1, 110},
+	}
+
+	shapes := book.AtOraclePrices([]int64{100, 105, 5}, 10)
+	if len(shapes) != 3 {
+		t.Fatalf("%d shapes for 3 oracle prices", len(shapes))
+	}
+	for _, s := range shapes {
+		want := expected[s.OraclePriceLots]
+		if s.BestBidLots == nil || s.BestAskLots == nil {
+			t.Fatalf("no best prices at %d", s.OraclePriceLots)
+		}
+		got := shape{*s.BestBidLots, s.BidBaseLots, s.BidQuoteLots, *s.BestAskLots, s.AskBaseLots, s.AskQuoteLots}
+		if got != want {
+			t.Errorf("shape %+v at %d, expected %+v", got, s.OraclePriceLots, want)
+		}
+	}
+}
+
+func TestAtOraclePricesSaturates(t *testing.T) {
+	book, err := BuildOrderbook([]BookOrder{
+		bid(10, math.MaxInt64/2+1),
+		bid(9, math.MaxInt64/2+1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shape := book.AtOraclePrices([]int64{1}, 0)[0]
+	if shape.BidBaseLots != math.MaxInt64 || shape.BidQuoteLots != math.MaxInt64 {
+		t.Fatalf("%d base and %d quote lots, expected both to saturate", shape.BidBaseLots, shape.BidQuoteLots)
+	}
+	if shape.BestAskLots != nil || shape.AskBaseLots != 0 {
+		t.Fatalf("asks on an empty side: %+v", shape)
+	}
+}
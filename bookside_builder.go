@@ -0,0 +1,248 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"math/bits"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// BookOrder is a resting order to place on a built BookSide.
+type BookOrder struct {
+	Side         Side
+	OraclePegged bool
+	// Price for fixed orders, offset from the oracle price for pegged ones
+	PriceLots     int64
+	PegLimit      int64 // Pegged orders only, -1 for no limit
+	Quantity      int64
+	Owner         solana.PublicKey
+	OwnerSlot     uint8
+	Timestamp     uint64
+	TimeInForce   uint16 // Seconds after Timestamp the order expires, 0 for never
+	ClientOrderID uint64
+}
+
+// BookSideBuilder places orders on an empty BookSide the way the program
+// inserts them into its order trees.
+//
+// Orders are given increasing sequence numbers in the order they are added,
+// so earlier orders have priority at the same price.
+type BookSideBuilder struct {
+	side     Side
+	bookSide *BookSide
+	seqNum   uint64
+}
+
+func NewBookSideBuilder(side Side) *BookSideBuilder {
+	bookSide := &BookSide{}
+	switch side {
+	case Bid:
+		bookSide.Nodes.OrderTreeType = uint8(Bids)
+	case Ask:
+		bookSide.Nodes.OrderTreeType = uint8(Asks)
+	}
+	return &BookSideBuilder{side: side, bookSide: bookSide}
+}
+
+// Add inserts the order into the fixed or oracle pegged tree.
+func (b *BookSideBuilder) Add(order BookOrder) error {
+	if order.Side != b.side {
+		return errors.New("order is for the other side")
+	}
+	if order.Quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+
+	var id OrderId
+	var component BookSideOrderTree
+	pegLimit := int64(-1)
+	if order.OraclePegged {
+		id = NewPeggedOrderId(b.side, order.PriceLots, b.seqNum)
+		component = OraclePeggedOrderTree
+		pegLimit = order.PegLimit
+	} else {
+		var err error
+		id, err = NewFixedOrderId(b.side, order.PriceLots, b.seqNum)
+		if err != nil {
+			return err
+		}
+		component = FixedOrderTree
+	}
+
+	leaf := LeafNode{
+		Tag:           uint8(leafNode),
+		OwnerSlot:     order.OwnerSlot,
+		TimeInForce:   order.TimeInForce,
+		Key:           id.Key(),
+		Owner:         order.Owner,
+		Quantity:      order.Quantity,
+		Timestamp:     order.Timestamp,
+		PegLimit:      pegLimit,
+		ClientOrderID: order.ClientOrderID,
+	}
+	if err := b.bookSide.Nodes.insertLeaf(b.bookSide.root(component), &leaf); err != nil {
+		return err
+	}
+	b.seqNum++
+	return nil
+}
+
+// Build returns the BookSide holding every order added so far. The builder
+// must not be used afterwards.
+func (b *BookSideBuilder) Build() *BookSide {
+	return b.bookSide
+}
+
+// BuildOrderbook places the orders on the side each of them is for.
+func BuildOrderbook(orders []BookOrder) (Orderbook, error) {
+	bids := NewBookSideBuilder(Bid)
+	asks := NewBookSideBuilder(Ask)
+	for _, order := range orders {
+		builder := bids
+		if order.Side == Ask {
+			builder = asks
+		}
+		if err := builder.Add(order); err != nil {
+			return Orderbook{}, err
+		}
+	}
+	return Orderbook{Bids: bids.Build(), Asks: asks.Build()}, nil
+}
+
+// insertLeaf adds the leaf to the tree under root, splitting the node where
+// its key leaves the tree's prefixes like the program does.
+func (o *OrderTreeNodes) insertLeaf(root *OrderTreeRoot, leaf *LeafNode) error {
+	newLeaf, err := newAnyNode(leaf)
+	if err != nil {
+		return err
+	}
+
+	// Empty tree, the leaf becomes the root
+	if root.LeafCount == 0 {
+		handle, err := o.insertNode(newLeaf)
+		if err != nil {
+			return err
+		}
+		root.MaybeNode = handle
+		root.LeafCount = 1
+		return nil
+	}
+
+	// Inner nodes on the path to the insert location, with the branch taken
+	type step struct {
+		handle NodeHandle
+		branch int
+	}
+	stack := make([]step, 0)
+
+	parentHandle := root.MaybeNode
+	for {
+		parent := o.Nodes[parentHandle]
+		parentKey := nodeKey(&parent)
+		if parentKey == leaf.Key && NodeTag(parent.Tag) == leafNode {
+			return errors.New("order key already exists")
+		}
+
+		sharedPrefixLen := uint32(keyLeadingZeros(bin.Uint128{Hi: parentKey.Hi ^ leaf.Key.Hi, Lo: parentKey.Lo ^ leaf.Key.Lo}))
+		if NodeTag(parent.Tag) == innerNode {
+			inner := parent.innerNode()
+			if sharedPrefixLen >= inner.PrefixLen {
+				branch := keyBit(leaf.Key, int(inner.PrefixLen))
+				stack = append(stack, step{handle: parentHandle, branch: branch})
+				parentHandle = inner.Children[branch]
+				continue
+			}
+		}
+
+		// The parent is a leaf, or an inner node whose prefix the leaf doesn't
+		// share: it moves down next to the leaf under a new inner node
+		if int(o.BumpIndex)+2 > len(o.Nodes) {
+			return errors.New("order tree is full")
+		}
+		leafBranch := keyBit(leaf.Key, int(sharedPrefixLen))
+		leafHandle, err := o.insertNode(newLeaf)
+		if err != nil {
+			return err
+		}
+		movedParentHandle, err := o.insertNode(parent)
+		if err != nil {
+			return err
+		}
+
+		leafExpiry := leaf.expiry()
+		parentExpiry := nodeEarliestExpiry(&parent)
+
+		newParent := InnerNode{
+			Tag:       uint8(innerNode),
+			PrefixLen: sharedPrefixLen,
+			Key:       leaf.Key,
+		}
+		newParent.Children[leafBranch] = leafHandle
+		newParent.Children[1-leafBranch] = movedParentHandle
+		newParent.ChildEarliestExpiry[leafBranch] = leafExpiry
+		newParent.ChildEarliestExpiry[1-leafBranch] = parentExpiry
+		if o.Nodes[parentHandle], err = newAnyNode(&newParent); err != nil {
+			return err
+		}
+
+		// Walk up the path lowering the earliest expiries the leaf precedes
+		outdatedExpiry, newExpiry := parentExpiry, leafExpiry
+		for i := len(stack) - 1; i >= 0 && newExpiry < outdatedExpiry; i-- {
+			inner := o.Nodes[stack[i].handle].innerNode()
+			if inner.ChildEarliestExpiry[stack[i].branch] != outdatedExpiry {
+				break
+			}
+			outdatedExpiry = innerEarliestExpiry(&inner)
+			inner.ChildEarliestExpiry[stack[i].branch] = newExpiry
+			newExpiry = innerEarliestExpiry(&inner)
+			if o.Nodes[stack[i].handle], err = newAnyNode(&inner); err != nil {
+				return err
+			}
+		}
+
+		root.LeafCount++
+		return nil
+	}
+}
+
+// insertNode stores the node at the next unused handle.
+func (o *OrderTreeNodes) insertNode(node AnyNode) (NodeHandle, error) {
+	if int(o.BumpIndex) >= len(o.Nodes) {
+		return 0, errors.New("order tree is full")
+	}
+	handle := NodeHandle(o.BumpIndex)
+	o.Nodes[handle] = node
+	o.BumpIndex++
+	return handle, nil
+}
+
+func nodeKey(node *AnyNode) bin.Uint128 {
+	if NodeTag(node.Tag) == innerNode {
+		return node.innerNode().Key
+	}
+	return node.leafNode().Key
+}
+
+func nodeEarliestExpiry(node *AnyNode) uint64 {
+	if NodeTag(node.Tag) == innerNode {
+		inner := node.innerNode()
+		return innerEarliestExpiry(&inner)
+	}
+	leaf := node.leafNode()
+	return leaf.expiry()
+}
+
+func innerEarliestExpiry(inner *InnerNode) uint64 {
+	if inner.ChildEarliestExpiry[0] < inner.ChildEarliestExpiry[1] {
+		return inner.ChildEarliestExpiry[0]
+	}
+	return inner.ChildEarliestExpiry[1]
+}
+
+func keyLeadingZeros(key bin.Uint128) int {
+	if key.Hi != 0 {
+		return bits.LeadingZeros64(key.Hi)
+	}
+	return 64 + bits.LeadingZeros64(key.Lo)
+}
@@ -1,20 +1,15 @@
 package openbookdexgolang
 
-import (
-	"log"
-	"math/big"
-)
-
 type BookSideIter struct {
-	FixedIter        *OrderTreeIter // Pointer to OrderTreeIter
-	OraclePeggedIter *OrderTreeIter // Pointer to OrderTreeIter
-	NowTs            uint64         // Current timestamp
-	OraclePriceLots  *int64         // Pointer to int64 to represent Option<i64>
+	FixedIter        OrderTreeIter
+	OraclePeggedIter OrderTreeIter
+	NowTs            uint64 // Current timestamp
+	OraclePriceLots  *int64 // Pointer to int64 to represent Option<i64>
 }
 
 type BookSideIterItem struct {
 	Handle    BookSideOrderHandle // handle holds the order handle
-	Node      *LeafNode           // node is a pointer to a LeafNode
+	Node      LeafNode            // node is a copy of the LeafNode
 	PriceLots int64               // priceLots represents the price in lots
 	State     OrderState          // state indicates the order state
 }
@@ -45,29 +40,39 @@ func (item *BookSideIterItem) IsValid() bool {
 	return item.State == Valid
 }
 
-func (iter *BookSideIter) Next() *BookSideIterItem {
+// Next returns the next best order of the side. It does not allocate.
+func (iter *BookSideIter) Next() (BookSideIterItem, bool) {
 	side := iter.FixedIter.Side()
 
-	var oPeek *struct {
-		handle NodeHandle
-		leaf   *LeafNode
-	}
+	var oPeek *LeafNodeWithHandle
+	var oHandle NodeHandle
+	var oNode LeafNode
+	oFound := false
 
-	if iter.OraclePeggedIter != nil {
-		oPeek = iter.OraclePeggedIter.Peek()
-		for oPeek != nil {
-			oNode := oPeek.leaf
-			orderState, _ := oraclePeggedPrice(*(iter.OraclePriceLots), oNode, side)
+	// Skip all the oracle pegged orders that aren't representable with the current oracle price
+	if iter.OraclePriceLots != nil {
+		for handle, ok := iter.OraclePeggedIter.Peek(); ok; handle, ok = iter.OraclePeggedIter.Peek() {
+			oNode = iter.OraclePeggedIter.OrderTree.LeafNode(handle)
+			orderState, _ := oraclePeggedPrice(*iter.OraclePriceLots, &oNode, side)
 			if orderState != Skipped {
+				oHandle, oFound = handle, true
 				break
 			}
-			oPeek = iter.OraclePeggedIter.Next()
+			iter.OraclePeggedIter.Next()
 		}
 	}
+	if oFound {
+		oPeek = &LeafNodeWithHandle{Handle: oHandle, LeafNode: &oNode}
+	}
 
-	fPeek := iter.FixedIter.Peek()
+	var fPeek *LeafNodeWithHandle
+	var fNode LeafNode
+	if fHandle, ok := iter.FixedIter.Peek(); ok {
+		fNode = iter.FixedIter.OrderTree.LeafNode(fHandle)
+		fPeek = &LeafNodeWithHandle{Handle: fHandle, LeafNode: &fNode}
+	}
 
-	better := rankOrders(
+	better, ok := rankOrders(
 		side,
 		fPeek,
 		oPeek,
@@ -76,8 +81,8 @@ func (iter *BookSideIter) Next() *BookSideIterItem {
 		iter.OraclePriceLots,
 	)
 
-	if better == nil {
-		return nil
+	if !ok {
+		return BookSideIterItem{}, false
 	}
 
 	switch better.Handle.OrderTree {
@@ -87,7 +92,7 @@ func (iter *BookSideIter) Next() *BookSideIterItem {
 		iter.OraclePeggedIter.Next()
 	}
 
-	return better
+	return better, true
 }
 
 func oraclePeggedPrice(oraclePriceLots int64, node *LeafNode, side Side) (OrderState, int64) {
@@ -110,119 +115,83 @@ func oraclePeggedPrice(oraclePriceLots int64, node *LeafNode, side Side) (OrderS
 
 func rankOrders(
 	side Side,
-	fixed *struct {
-		handle NodeHandle
-		leaf   *LeafNode
-	},
-	oraclePegged *struct {
-		handle NodeHandle
-		leaf   *LeafNode
-	},
+	fixed *LeafNodeWithHandle,
+	oraclePegged *LeafNodeWithHandle,
 	returnWorse bool,
 	nowTs uint64,
 	oraclePriceLots *int64, // Simulate Option<i64>
-) *BookSideIterItem {
-	// Enrich oraclePegged if oracle_price_lots is present
-	var oraclePegged1 *struct {
-		handle NodeHandle
-		leaf   *LeafNode
-		price  int64
-		state  OrderState
-	}
-
-	if oraclePriceLots != nil {
-		state, price := oraclePeggedPrice(*oraclePriceLots, oraclePegged.leaf, side)
-		oraclePegged1 = &struct {
-			handle NodeHandle
-			leaf   *LeafNode
-			price  int64
-			state  OrderState
-		}{
-			handle: oraclePegged.handle,
-			leaf:   oraclePegged.leaf,
-			price:  price,
-			state:  state,
-		}
+) (BookSideIterItem, bool) {
+	// Oracle pegged orders can only be ranked with an oracle price
+	if oraclePriceLots == nil {
+		oraclePegged = nil
 	}
 
 	// Determine ranking logic for fixed and oracle pegged
-	if fixed != nil && oraclePegged1 != nil {
-		isBetter := func(a, b uint64) bool {
-			if side == Bid {
-				return a > b
-			}
-			return a < b
+	if fixed != nil && oraclePegged != nil {
+		state, price := oraclePeggedPrice(*oraclePriceLots, oraclePegged.LeafNode, side)
+
+		// Rank the pegged order as a fixed order at its current price
+		oracleId := oraclePegged.LeafNode.OrderId().withPriceData(uint64(price))
+		fixedId := fixed.LeafNode.OrderId()
+		var isBetter bool
+		if side == Bid {
+			isBetter = oracleId.Less(fixedId)
+		} else {
+			isBetter = fixedId.Less(oracleId)
 		}
 
-		oracleKey := keyForFixedPrice(oraclePegged1.leaf.Key.BigInt(), oraclePegged1.price)
-		if isBetter(fixed.leaf.Key.BigInt().Uint64(), oracleKey.Uint64()) != returnWorse {
-			return fixedToResult(fixed, nowTs)
+		if isBetter != returnWorse {
+			return fixedToResult(fixed, nowTs), true
 		} else {
-			return oraclePeggedToResult(oraclePegged1, nowTs)
+			return oraclePeggedToResult(oraclePegged, price, state, nowTs), true
 		}
-	} else if fixed == nil && oraclePegged1 != nil {
-		return oraclePeggedToResult(oraclePegged1, nowTs)
-	} else if fixed != nil && oraclePegged1 == nil {
-		return fixedToResult(fixed, nowTs)
+	} else if fixed == nil && oraclePegged != nil {
+		state, price := oraclePeggedPrice(*oraclePriceLots, oraclePegged.LeafNode, side)
+		return oraclePeggedToResult(oraclePegged, price, state, nowTs), true
+	} else if fixed != nil && oraclePegged == nil {
+		return fixedToResult(fixed, nowTs), true
 	} else {
-		return nil
-	}
-}
-
-func keyForFixedPrice(key *big.Int, priceLots int64) *big.Int {
-	// We know this can never fail, because oracle pegged price will always be >= 1
-	if priceLots < 1 {
-		log.Fatal("priceLots must be >= 1")
-	}
-	priceData, _ := fixedPriceData(priceLots)
-	if priceData == nil {
-		log.Fatal("failed to get price data")
+		return BookSideIterItem{}, false
 	}
-	upper := uint64(*priceData) << 64
-	lower := uint64(key.Uint64())
-	return upper | lower
 }
 
-func fixedToResult(fixed *struct {
-	handle NodeHandle
-	leaf   *LeafNode
-}, nowTs uint64) *BookSideIterItem {
-	handle, node := fixed
+func fixedToResult(fixed *LeafNodeWithHandle, nowTs uint64) BookSideIterItem {
+	handle, node := fixed.Handle, fixed.LeafNode
 
 	// Check if the node is expired
-	expired := node.IsExpired(nowTs)
+	state := Valid
+	if node.IsExpired(nowTs) {
+		state = Invalid
+	}
 
 	// Create and return the result
-	return &BookSideIterItem{
+	return BookSideIterItem{
 		Handle: BookSideOrderHandle{
-			OrderTree: Fixed,
+			OrderTree: FixedOrderTree,
 			Node:      handle,
 		},
-		Node:      node,
-		PriceLots: int64(node.Key), // Assuming PriceData is stored in node.Key (modify if necessary)
-		State:     getOrderState(expired),
+		Node:      *node,
+		PriceLots: int64(node.PriceData()),
+		State:     state,
 	}
 }
 
-func oraclePeggedToResult(pegged *struct {
-	handle NodeHandle
-	leaf   *LeafNode
-	price  int64
-	state  OrderState
-}, nowTs uint64) *BookSideIterItem {
-	handle, node, priceLots, state := pegged
+func oraclePeggedToResult(pegged *LeafNodeWithHandle, priceLots int64, state OrderState, nowTs uint64) BookSideIterItem {
+	handle, node := pegged.Handle, pegged.LeafNode
 
 	// Check if the node is expired
-	expired := node.IsExpired(nowTs)
+	if node.IsExpired(nowTs) {
+		state = Invalid
+	}
 
 	// Create and return the result
-	return &BookSideIterItem{
+	return BookSideIterItem{
 		Handle: BookSideOrderHandle{
-			OrderTree: OraclePegged,
+			OrderTree: OraclePeggedOrderTree,
 			Node:      handle,
 		},
-		Node:      node,
+		Node:      *node,
 		PriceLots: priceLots,
-		State:     getOrderStateForPegged(expired, state),
+		State:     state,
 	}
 }
@@ -0,0 +1,55 @@
+package openbookdexgolang
+
+import "testing"
+
+// newFullBookSide returns an ask side whose fixed tree uses every node: 512
+// leaves joined by 511 inner nodes.
+func newFullBookSide(t testing.TB) *BookSide {
+	t.Helper()
+	builder := NewBookSideBuilder(Ask)
+	for i := 0; i < MAX_ORDERTREE_NODES/2; i++ {
+		if err := builder.Add(ask(int64(100+i%64), int64(1+i%7))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bookSide := builder.Build()
+	if bookSide.Nodes.BumpIndex < MAX_ORDERTREE_NODES-1 {
+		t.Fatalf("%d nodes used, expected a full side", bookSide.Nodes.BumpIndex)
+	}
+	return bookSide
+}
+
+func TestBookSideIterNextDoesNotAllocate(t *testing.T) {
+	bookSide := newFullBookSide(t)
+	oraclePriceLots := int64(100)
+
+	// Every run walks a copy of the same fresh iterator
+	start := *bookSide.IterAllIncludingInvalid(1, &oraclePriceLots)
+	var iter BookSideIter
+	allocs := testing.AllocsPerRun(100, func() {
+		iter = start
+		count := 0
+		for _, ok := iter.Next(); ok; _, ok = iter.Next() {
+			count++
+		}
+		if count != MAX_ORDERTREE_NODES/2 {
+			t.Fatalf("%d orders yielded, expected %d", count, MAX_ORDERTREE_NODES/2)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations per iteration of the side", allocs)
+	}
+}
+
+func BenchmarkBookSideIterator(b *testing.B) {
+	bookSide := newFullBookSide(b)
+	oraclePriceLots := int64(100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		iter := bookSide.IterAllIncludingInvalid(1, &oraclePriceLots)
+		for _, ok := iter.Next(); ok; _, ok = iter.Next() {
+		}
+	}
+}
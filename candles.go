@@ -0,0 +1,145 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	MIN_CANDLE_INTERVAL = time.Second
+	MAX_CANDLE_INTERVAL = 24 * time.Hour
+)
+
+var ErrLateFill = errors.New("fill belongs to an already flushed candle")
+
+// Candle is an OHLCV bar in ui units.
+type Candle struct {
+	// Unix timestamp in seconds of the start of the bar
+	Start    uint64
+	Interval time.Duration
+
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+
+	// Base and quote volume over all fills
+	BaseVolume  float64
+	QuoteVolume float64
+	// Base volume split by the taker side: bids are taker buys, asks taker sells
+	TakerBuyBaseVolume  float64
+	TakerSellBaseVolume float64
+
+	// Fees in ui quote. MakerFees is negative when makers earned rebates.
+	TakerFees float64
+	MakerFees float64
+
+	Trades int
+}
+
+// CandleBuilder aggregates fill events of one market into candles.
+type CandleBuilder struct {
+	market          *Market
+	interval        time.Duration
+	intervalSeconds uint64
+	candles         map[uint64]*Candle
+	// Candles starting before this have been flushed
+	flushedBefore uint64
+}
+
+func NewCandleBuilder(market *Market, interval time.Duration) (*CandleBuilder, error) {
+	if interval < MIN_CANDLE_INTERVAL || interval > MAX_CANDLE_INTERVAL {
+		return nil, errors.New("candle interval must be between 1s and 1d")
+	}
+	if interval%time.Second != 0 {
+		return nil, errors.New("candle interval must be a whole number of seconds")
+	}
+	return &CandleBuilder{
+		market:          market,
+		interval:        interval,
+		intervalSeconds: uint64(interval / time.Second),
+		candles:         make(map[uint64]*Candle),
+	}, nil
+}
+
+// Add folds a fill into the candle covering its timestamp. Fills of a candle
+// must be added in the order they happened for Open and Close to be right.
+// Fills of a candle that was already flushed are dropped with ErrLateFill.
+func (b *CandleBuilder) Add(fill *FillEvent) error {
+	start := fill.Timestamp - fill.Timestamp%b.intervalSeconds
+	if start < b.flushedBefore {
+		return ErrLateFill
+	}
+	price := b.market.PriceLotsToUi(fill.Price)
+	base := b.market.BaseLotsToUi(fill.Quantity)
+
+	quoteNative := fill.Price * fill.Quantity * b.market.QuoteLotSize
+	takerFees := b.market.QuoteNativeToUi(int64(b.market.TakerFeesCeil(uint64(quoteNative))))
+	makerFees := b.market.QuoteNativeToUi(int64(b.market.MakerFeesCeil(uint64(quoteNative))))
+	makerFees -= b.market.QuoteNativeToUi(int64(b.market.MakerRebateFloor(uint64(quoteNative))))
+
+	candle, ok := b.candles[start]
+	if !ok {
+		candle = &Candle{
+			Start:    start,
+			Interval: b.interval,
+			Open:     price,
+			High:     price,
+			Low:      price,
+		}
+		b.candles[start] = candle
+	}
+
+	if price > candle.High {
+		candle.High = price
+	}
+	if price < candle.Low {
+		candle.Low = price
+	}
+	candle.Close = price
+
+	candle.BaseVolume += base
+	candle.QuoteVolume += b.market.QuoteNativeToUi(quoteNative)
+	if Side(fill.TakerSide) == Bid {
+		candle.TakerBuyBaseVolume += base
+	} else {
+		candle.TakerSellBaseVolume += base
+	}
+	candle.TakerFees += takerFees
+	candle.MakerFees += makerFees
+	candle.Trades++
+	return nil
+}
+
+// Flush removes and returns, oldest first, every candle that ended at or
+// before the given unix timestamp. Later fills for those candles are dropped.
+func (b *CandleBuilder) Flush(before uint64) []Candle {
+	if flushedBefore := before - before%b.intervalSeconds; flushedBefore > b.flushedBefore {
+		b.flushedBefore = flushedBefore
+	}
+	candles := make([]Candle, 0)
+	for start, candle := range b.candles {
+		if start+b.intervalSeconds <= before {
+			candles = append(candles, *candle)
+			delete(b.candles, start)
+		}
+	}
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Start < candles[j].Start
+	})
+	return candles
+}
+
+// Candles returns every candle built so far, including the open ones, oldest
+// first.
+func (b *CandleBuilder) Candles() []Candle {
+	candles := make([]Candle, 0, len(b.candles))
+	for _, candle := range b.candles {
+		candles = append(candles, *candle)
+	}
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Start < candles[j].Start
+	})
+	return candles
+}
@@ -0,0 +1,53 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func addTestFill(t *testing.T, builder *CandleBuilder, priceLots, quantity int64, timestamp uint64) error {
+	t.Helper()
+	event := newTestFill(t, priceLots, quantity, timestamp)
+	fill, err := event.Fill()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return builder.Add(fill)
+}
+
+func TestCandleBuilderDropsLateFills(t *testing.T) {
+	builder, err := NewCandleBuilder(newTestMarketAccount(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fill := range []struct {
+		priceLots int64
+		timestamp uint64
+	}{{100, 60}, {101, 90}, {102, 120}} {
+		if err := addTestFill(t, builder, fill.priceLots, 1, fill.timestamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Flushing mid candle only flushes the candles that ended
+	flushed := builder.Flush(150)
+	if len(flushed) != 1 || flushed[0].Start != 60 || flushed[0].Trades != 2 {
+		t.Fatalf("flushed %+v, expected the candle at 60 with 2 trades", flushed)
+	}
+
+	if err := addTestFill(t, builder, 99, 1, 100); !errors.Is(err, ErrLateFill) {
+		t.Fatalf("late fill added with %v, expected %v", err, ErrLateFill)
+	}
+	if err := addTestFill(t, builder, 103, 1, 130); err != nil {
+		t.Fatal(err)
+	}
+
+	candles := builder.Candles()
+	if len(candles) != 1 || candles[0].Start != 120 || candles[0].Trades != 2 || candles[0].Close != 103 {
+		t.Fatalf("candles %+v, expected the candle at 120 with 2 trades closing at 103", candles)
+	}
+	if flushed := builder.Flush(180); len(flushed) != 1 || flushed[0].Start != 120 {
+		t.Fatalf("flushed %+v, expected the candle at 120", flushed)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+type marketResponse struct {
+	Address solana.PublicKey `json:"address"`
+	Label   string           `json:"label"`
+	Market  *openbook.Market `json:"market"`
+}
+
+type quoteResponse struct {
+	Market solana.PublicKey `json:"market"`
+	Quote  *openbook.Quote  `json:"quote"`
+}
+
+type l2Response struct {
+	Market solana.PublicKey   `json:"market"`
+	Bids   []openbook.L2Level `json:"bids"`
+	Asks   []openbook.L2Level `json:"asks"`
+}
+
+type l3Response struct {
+	Market solana.PublicKey   `json:"market"`
+	Bids   []openbook.L3Order `json:"bids"`
+	Asks   []openbook.L3Order `json:"asks"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newHandler(store *marketStore) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /markets", store.handleMarkets)
+	mux.HandleFunc("GET /quote", store.handleQuote)
+	mux.HandleFunc("GET /book/{market}/l2", store.handleL2)
+	mux.HandleFunc("GET /book/{market}/l3", store.handleL3)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func (s *marketStore) handleMarkets(w http.ResponseWriter, r *http.Request) {
+	markets := make([]marketResponse, 0, len(s.keys))
+	for _, key := range s.keys {
+		obm := s.registry.Get(key)
+		markets = append(markets, marketResponse{Address: key, Label: obm.Label(), Market: obm.Market()})
+	}
+	writeJSON(w, http.StatusOK, markets)
+}
+
+func (s *marketStore) handleQuote(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	inputMint, err := solana.PublicKeyFromBase58(query.Get("inputMint"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid inputMint"))
+		return
+	}
+	outputMint, err := solana.PublicKeyFromBase58(query.Get("outputMint"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid outputMint"))
+		return
+	}
+	amount, err := strconv.ParseUint(query.Get("amount"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid amount"))
+		return
+	}
+	var marketFilter *solana.PublicKey
+	if address := query.Get("market"); address != "" {
+		key, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("invalid market"))
+			return
+		}
+		marketFilter = &key
+	}
+
+	params := &openbook.QuoteParams{InAmount: amount, InputMint: inputMint, OutputMint: outputMint}
+	var best *quoteResponse
+	var quoteErr error
+	for _, key := range s.keys {
+		obm := s.registry.Get(key)
+		if (marketFilter != nil && key != *marketFilter) || !tradesPair(obm, inputMint, outputMint) {
+			continue
+		}
+		quote, err := obm.Quote(params)
+		if err != nil {
+			quoteErr = err
+			continue
+		}
+		if best == nil || quote.OutAmount > best.Quote.OutAmount {
+			best = &quoteResponse{Market: key, Quote: quote}
+		}
+	}
+
+	if best == nil {
+		if quoteErr != nil {
+			writeError(w, http.StatusInternalServerError, quoteErr)
+			return
+		}
+		writeError(w, http.StatusNotFound, errors.New("no market for this pair"))
+		return
+	}
+	writeJSON(w, http.StatusOK, best)
+}
+
+func tradesPair(obm *openbook.OpenBookMarket, inputMint, outputMint solana.PublicKey) bool {
+	mints := obm.ReserveMints()
+	return (mints[0] == inputMint && mints[1] == outputMint) ||
+		(mints[0] == outputMint && mints[1] == inputMint)
+}
+
+// withMarket calls f with the current snapshot of the market in the request
+// path.
+func (s *marketStore) withMarket(w http.ResponseWriter, r *http.Request, f func(key solana.PublicKey, obm *openbook.OpenBookMarket)) {
+	key, err := solana.PublicKeyFromBase58(r.PathValue("market"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid market"))
+		return
+	}
+	obm := s.registry.Get(key)
+	if obm == nil {
+		writeError(w, http.StatusNotFound, errors.New("unknown market"))
+		return
+	}
+	f(key, obm)
+}
+
+func (s *marketStore) handleL2(w http.ResponseWriter, r *http.Request) {
+	depth := 20
+	if value := r.URL.Query().Get("depth"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, errors.New("invalid depth"))
+			return
+		}
+		depth = parsed
+	}
+
+	s.withMarket(w, r, func(key solana.PublicKey, obm *openbook.OpenBookMarket) {
+		book := obm.Orderbook()
+		writeJSON(w, http.StatusOK, l2Response{
+			Market: key,
+			Bids:   book.Bids.L2(obm.Timestamp(), obm.OraclePriceLots(), depth),
+			Asks:   book.Asks.L2(obm.Timestamp(), obm.OraclePriceLots(), depth),
+		})
+	})
+}
+
+func (s *marketStore) handleL3(w http.ResponseWriter, r *http.Request) {
+	s.withMarket(w, r, func(key solana.PublicKey, obm *openbook.OpenBookMarket) {
+		book := obm.Orderbook()
+		writeJSON(w, http.StatusOK, l3Response{
+			Market: key,
+			Bids:   book.Bids.L3(obm.Timestamp(), obm.OraclePriceLots()),
+			Asks:   book.Asks.L3(obm.Timestamp(), obm.OraclePriceLots()),
+		})
+	})
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+var (
+	testMarketKey = solana.PublicKey{1}
+	testBaseMint  = solana.PublicKey{2}
+	testQuoteMint = solana.PublicKey{3}
+	testOracle    = solana.PublicKey{4}
+	testOwner     = solana.PublicKey{5}
+)
+
+// mapSource serves accounts from a map that tests may replace between
+// refreshes.
+type mapSource struct {
+	mu       sync.Mutex
+	accounts map[solana.PublicKey][]byte
+}
+
+func (s *mapSource) GetAccounts(ctx context.Context, keys []solana.PublicKey) (map[solana.PublicKey][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := make(map[solana.PublicKey][]byte, len(keys))
+	for _, key := range keys {
+		if data, ok := s.accounts[key]; ok {
+			accounts[key] = data
+		}
+	}
+	return accounts, nil
+}
+
+func (s *mapSource) set(key solana.PublicKey, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[key] = data
+}
+
+func encodeAccount(t *testing.T, name string, v interface{}) []byte {
+	t.Helper()
+	hash := sha256.Sum256([]byte("account:" + name))
+	buf := bytes.NewBuffer(nil)
+	buf.Write(hash[:8])
+	if err := bin.NewBorshEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeClock(t *testing.T, slot uint64) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBinEncoder(buf).Encode(&openbook.Clock{Slot: slot, UnixTimestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// encodePythPrice lays out a trading Pyth v2 price account with exponent 0.
+func encodePythPrice(price int64, pubSlot uint64) []byte {
+	data := make([]byte, 240)
+	binary.LittleEndian.PutUint32(data, 0xa1b2c3d4)
+	binary.LittleEndian.PutUint32(data[4:], 2)
+	binary.LittleEndian.PutUint32(data[8:], 3)
+	binary.LittleEndian.PutUint64(data[208:], uint64(price))
+	binary.LittleEndian.PutUint32(data[224:], 1)
+	binary.LittleEndian.PutUint64(data[232:], pubSlot)
+	return data
+}
+
+// newTestStore serves a market without decimals or fees, with a bid at 99, an
+// ask at 110 and a pegged ask 2 lots above its oracle, which is at 100.
+func newTestStore(t *testing.T) (*marketStore, *mapSource) {
+	t.Helper()
+	market := &openbook.Market{
+		Bids:         solana.PublicKey{6},
+		Asks:         solana.PublicKey{7},
+		EventHeap:    solana.PublicKey{8},
+		OracleA:      openbook.NonZeroPubkeyOption{Key: testOracle},
+		OracleConfig: openbook.OracleConfig{ConfFilter: 0.1, MaxStalenessSlots: 10},
+		BaseMint:     testBaseMint,
+		QuoteMint:    testQuoteMint,
+		BaseLotSize:  1,
+		QuoteLotSize: 1,
+	}
+	copy(market.Name[:], "BASE-QUOTE")
+
+	book, err := openbook.BuildOrderbook([]openbook.BookOrder{
+		{Side: openbook.Bid, PriceLots: 99, Quantity: 1, Owner: testOwner},
+		{Side: openbook.Ask, PriceLots: 110, Quantity: 1, Owner: testOwner},
+		{Side: openbook.Ask, OraclePegged: true, PriceLots: 2, PegLimit: -1, Quantity: 3, Owner: testOwner},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &mapSource{accounts: map[solana.PublicKey][]byte{
+		testMarketKey:            encodeAccount(t, "Market", market),
+		market.Bids:              encodeAccount(t, "BookSide", book.Bids),
+		market.Asks:              encodeAccount(t, "BookSide", book.Asks),
+		market.EventHeap:         encodeAccount(t, "EventHeap", &openbook.EventHeap{}),
+		testOracle:               encodePythPrice(100, 50),
+		solana.SysVarClockPubkey: encodeClock(t, 55),
+	}}
+	store, err := newMarketStore(context.Background(), source, []solana.PublicKey{testMarketKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, source
+}
+
+func get(t *testing.T, handler http.Handler, target string, status int, v interface{}) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	if recorder.Code != status {
+		t.Fatalf("GET %s: status %d, expected %d: %s", target, recorder.Code, status, recorder.Body)
+	}
+	if v != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+			t.Fatalf("GET %s: %v", target, err)
+		}
+	}
+}
+
+func quoteTarget(amount string) string {
+	return "/quote?inputMint=" + testQuoteMint.String() + "&outputMint=" + testBaseMint.String() + "&amount=" + amount
+}
+
+func TestHandleMarkets(t *testing.T) {
+	store, _ := newTestStore(t)
+	var markets []marketResponse
+	get(t, newHandler(store), "/markets", http.StatusOK, &markets)
+	if len(markets) != 1 || markets[0].Address != testMarketKey || markets[0].Label != "BASE-QUOTE" {
+		t.Fatalf("markets %+v", markets)
+	}
+}
+
+func TestHandleQuotePricesPeggedOrders(t *testing.T) {
+	store, source := newTestStore(t)
+	handler := newHandler(store)
+
+	var response quoteResponse
+	get(t, handler, quoteTarget("306"), http.StatusOK, &response)
+	if response.Market != testMarketKey || response.Quote.OutAmount != 3 || response.Quote.NotEnoughLiquidity {
+		t.Fatalf("quote %+v, expected the 3 lots of the pegged ask at 102", response.Quote)
+	}
+
+	// Once the oracle is stale the pegged ask is ignored
+	source.set(solana.SysVarClockPubkey, encodeClock(t, 61))
+	if err := store.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	get(t, handler, quoteTarget("306"), http.StatusOK, &response)
+	if response.Quote.OutAmount != 1 || !response.Quote.NotEnoughLiquidity {
+		t.Fatalf("quote %+v, expected only the ask at 110", response.Quote)
+	}
+
+	// And priced again at the oracle's new price
+	source.set(testOracle, encodePythPrice(90, 60))
+	if err := store.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	get(t, handler, quoteTarget("276"), http.StatusOK, &response)
+	if response.Quote.OutAmount != 3 {
+		t.Fatalf("quote %+v, expected the 3 lots of the pegged ask at 92", response.Quote)
+	}
+}
+
+func TestHandleBooks(t *testing.T) {
+	store, source := newTestStore(t)
+	handler := newHandler(store)
+
+	var l2 l2Response
+	get(t, handler, "/book/"+testMarketKey.String()+"/l2", http.StatusOK, &l2)
+	expectedAsks := []openbook.L2Level{{PriceLots: 102, BaseLots: 3, Orders: 1}, {PriceLots: 110, BaseLots: 1, Orders: 1}}
+	if len(l2.Asks) != 2 || l2.Asks[0] != expectedAsks[0] || l2.Asks[1] != expectedAsks[1] {
+		t.Fatalf("asks %+v, expected %+v", l2.Asks, expectedAsks)
+	}
+	if len(l2.Bids) != 1 || l2.Bids[0].PriceLots != 99 {
+		t.Fatalf("bids %+v", l2.Bids)
+	}
+
+	get(t, handler, "/book/"+testMarketKey.String()+"/l2?depth=1", http.StatusOK, &l2)
+	if len(l2.Asks) != 1 || l2.Asks[0].PriceLots != 102 {
+		t.Fatalf("asks %+v at depth 1", l2.Asks)
+	}
+
+	var l3 l3Response
+	get(t, handler, "/book/"+testMarketKey.String()+"/l3", http.StatusOK, &l3)
+	if len(l3.Asks) != 2 || l3.Asks[0].OrderTree != openbook.OraclePeggedOrderTree || l3.Asks[0].PriceLots != 102 {
+		t.Fatalf("asks %+v", l3.Asks)
+	}
+
+	// Without the oracle account the pegged ask is left out
+	source.mu.Lock()
+	delete(source.accounts, testOracle)
+	source.mu.Unlock()
+	if err := store.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	get(t, handler, "/book/"+testMarketKey.String()+"/l3", http.StatusOK, &l3)
+	if len(l3.Asks) != 1 || l3.Asks[0].PriceLots != 110 {
+		t.Fatalf("asks %+v without an oracle", l3.Asks)
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	store, _ := newTestStore(t)
+	handler := newHandler(store)
+	other := solana.PublicKey{9}.String()
+
+	cases := []struct {
+		target string
+		status int
+	}{
+		{"/quote?inputMint=x&outputMint=" + testBaseMint.String() + "&amount=1", http.StatusBadRequest},
+		{"/quote?inputMint=" + testQuoteMint.String() + "&outputMint=x&amount=1", http.StatusBadRequest},
+		{quoteTarget("-1"), http.StatusBadRequest},
+		{quoteTarget("1") + "&market=x", http.StatusBadRequest},
+		{quoteTarget("1") + "&market=" + other, http.StatusNotFound},
+		{"/quote?inputMint=" + testQuoteMint.String() + "&outputMint=" + other + "&amount=1", http.StatusNotFound},
+		{"/book/x/l2", http.StatusBadRequest},
+		{"/book/" + other + "/l2", http.StatusNotFound},
+		{"/book/" + other + "/l3", http.StatusNotFound},
+		{"/book/" + testMarketKey.String() + "/l2?depth=-1", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		var response errorResponse
+		get(t, handler, c.target, c.status, &response)
+		if response.Error == "" {
+			t.Errorf("GET %s: no error message", c.target)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Command openbook-quoted serves quotes and books of a set of OpenBook v2
+// markets over HTTP as JSON.
+//
+// Endpoints:
+//
+//	GET /markets
+//	GET /quote?inputMint=<mint>&outputMint=<mint>&amount=<native>[&market=<address>]
+//	GET /book/{market}/l2[?depth=20]
+//	GET /book/{market}/l3
+//
+// Market state is refreshed every -interval, either from a directory of
+// account dumps or from an RPC node. Requests are answered lock free from the
+// last complete snapshot. Orders are expired at the time of the Clock sysvar,
+// so the accounts directory must also hold a dump of
+// SysvarC1ock11111111111111111111111111111111. Oracle pegged orders are priced
+// from the Pyth or stub oracle accounts of each market, and ignored while
+// those are missing, stale or not confident enough.
+//
+// With -rpc, the accounts of all markets are fetched with batched
+// getMultipleAccounts calls. A market's snapshot is only replaced by books
+// whose bids and asks were read at the same slot, no older than its own.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to serve http on")
+	markets := flag.String("markets", "", "comma separated market addresses")
+	accountsDir := flag.String("accounts-dir", "", "directory of <address>.json account dumps")
+	rpcURL := flag.String("rpc", "", "url of an rpc node to fetch accounts from, instead of -accounts-dir")
+	interval := flag.Duration("interval", time.Second, "how often market accounts are refreshed")
+	flag.Parse()
+
+	if *markets == "" || (*accountsDir == "") == (*rpcURL == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	keys := make([]solana.PublicKey, 0)
+	for _, address := range strings.Split(*markets, ",") {
+		key, err := solana.PublicKeyFromBase58(strings.TrimSpace(address))
+		if err != nil {
+			log.Fatalf("invalid market address %q: %v", address, err)
+		}
+		keys = append(keys, key)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var source openbook.AccountSource = &openbook.DirAccountSource{Dir: *accountsDir}
+	if *rpcURL != "" {
+		source = openbook.NewRPCAccountSource(openbook.NewHTTPRPCClient(*rpcURL))
+	}
+	store, err := newMarketStore(ctx, source, keys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go store.run(ctx, *interval)
+
+	server := &http.Server{Addr: *listen, Handler: newHandler(store)}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving %d markets on %s", len(keys), *listen)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
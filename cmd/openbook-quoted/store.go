@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+// marketStore keeps the markets of a registry up to date. A snapshot is only
+// published once all of its accounts were applied, so readers never see a half
+// updated market.
+type marketStore struct {
+	source   openbook.AccountSource
+	keys     []solana.PublicKey
+	registry *openbook.MarketRegistry
+}
+
+func newMarketStore(ctx context.Context, source openbook.AccountSource, keys []solana.PublicKey) (*marketStore, error) {
+	accounts, err := source.GetAccounts(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := openbook.NewMarketRegistry()
+	for _, key := range keys {
+		data, ok := accounts[key]
+		if !ok {
+			return nil, fmt.Errorf("market %s not found", key)
+		}
+		market, err := openbook.DecodeMarket(data)
+		if err != nil {
+			return nil, fmt.Errorf("market %s: %w", key, err)
+		}
+		registry.Publish(openbook.NewOpenBookMarket(key, market))
+	}
+
+	store := &marketStore{
+		source:   source,
+		keys:     keys,
+		registry: registry,
+	}
+	if err := store.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *marketStore) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refresh(ctx); err != nil {
+				log.Printf("refresh: %v", err)
+			}
+		}
+	}
+}
+
+// marketsSource fetches the accounts of many markets at once, with the slots
+// they were read at.
+type marketsSource interface {
+	GetMarketsAccounts(ctx context.Context, markets []*openbook.OpenBookMarket) (*openbook.SlotAccounts, error)
+}
+
+// refresh updates every market. Markets failing to update keep their previous
+// snapshot.
+func (s *marketStore) refresh(ctx context.Context) error {
+	if source, ok := s.source.(marketsSource); ok {
+		return s.refreshAtSlot(ctx, source)
+	}
+
+	var firstErr error
+	for _, key := range s.keys {
+		obm := s.registry.Get(key)
+		keys := append(append([]solana.PublicKey{}, obm.GetAccountsToUpdate()...), obm.OracleAccounts()...)
+		accounts, err := s.source.GetAccounts(ctx, keys)
+		if err == nil {
+			err = s.registry.Modify(key, func(next *openbook.OpenBookMarket) error {
+				if err := next.Update(accounts); err != nil {
+					return err
+				}
+				updateOraclePrice(next, accounts)
+				return nil
+			})
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("market %s: %w", key, err)
+		}
+	}
+	return firstErr
+}
+
+// refreshAtSlot fetches the accounts of every market in one batch, and only
+// applies the books read at a consistent slot.
+func (s *marketStore) refreshAtSlot(ctx context.Context, source marketsSource) error {
+	markets := make([]*openbook.OpenBookMarket, 0, len(s.keys))
+	for _, key := range s.keys {
+		markets = append(markets, s.registry.Get(key))
+	}
+	accounts, err := source.GetMarketsAccounts(ctx, markets)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	for _, key := range s.keys {
+		err := s.registry.Modify(key, func(next *openbook.OpenBookMarket) error {
+			if err := next.UpdateAtSlot(accounts); err != nil {
+				return err
+			}
+			updateOraclePrice(next, accounts.Accounts)
+			return nil
+		})
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("market %s: %w", key, err)
+		}
+	}
+	return firstErr
+}
+
+// updateOraclePrice prices the pegged orders of the snapshot about to be
+// published. A market whose oracle can't be used still gets its new book, its
+// pegged orders are ignored until the oracle recovers.
+func updateOraclePrice(obm *openbook.OpenBookMarket, accounts map[solana.PublicKey][]byte) {
+	if err := obm.UpdateOraclePrice(accounts); err != nil {
+		log.Printf("market %s: pegged orders ignored: %v", obm.Key(), err)
+	}
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+func loadMarket(opts *options) (solana.PublicKey, *openbook.Market, error) {
+	if opts.marketPath == "" {
+		return solana.PublicKey{}, nil, errors.New("-market is required")
+	}
+	address, data, err := openbook.LoadAccountFile(opts.marketPath)
+	if err != nil {
+		return solana.PublicKey{}, nil, err
+	}
+	market, err := openbook.DecodeMarket(data)
+	if err != nil {
+		return solana.PublicKey{}, nil, fmt.Errorf("%s: %w", opts.marketPath, err)
+	}
+	return address, market, nil
+}
+
+func loadBookSide(path string, name string) (*openbook.BookSide, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-%s is required", name)
+	}
+	_, data, err := openbook.LoadAccountFile(path)
+	if err != nil {
+		return nil, err
+	}
+	bookSide, err := openbook.DecodeBookSide(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return bookSide, nil
+}
+
+func loadEventHeap(path string) (*openbook.EventHeap, error) {
+	if path == "" {
+		return nil, errors.New("-event-heap is required")
+	}
+	_, data, err := openbook.LoadAccountFile(path)
+	if err != nil {
+		return nil, err
+	}
+	eventHeap, err := openbook.DecodeEventHeap(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return eventHeap, nil
+}
+
+func loadBook(opts *options) (*openbook.Market, openbook.Orderbook, error) {
+	_, market, err := loadMarket(opts)
+	if err != nil {
+		return nil, openbook.Orderbook{}, err
+	}
+	bids, err := loadBookSide(opts.bidsPath, "bids")
+	if err != nil {
+		return nil, openbook.Orderbook{}, err
+	}
+	asks, err := loadBookSide(opts.asksPath, "asks")
+	if err != nil {
+		return nil, openbook.Orderbook{}, err
+	}
+	return market, openbook.Orderbook{Bids: bids, Asks: asks}, nil
+}
+
+// timestamp returns the unix timestamp orders are expired at: -now when set,
+// else the one of the -clock dump, else the current time.
+func (opts *options) timestamp() (uint64, error) {
+	if opts.nowTs != 0 {
+		return opts.nowTs, nil
+	}
+	if opts.clockPath == "" {
+		return uint64(time.Now().Unix()), nil
+	}
+	_, data, err := openbook.LoadAccountFile(opts.clockPath)
+	if err != nil {
+		return 0, err
+	}
+	clock, err := openbook.DecodeClock(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", opts.clockPath, err)
+	}
+	return uint64(clock.UnixTimestamp), nil
+}
+
+func (opts *options) oraclePrice() *int64 {
+	if opts.oraclePriceLots == 0 {
+		return nil
+	}
+	return &opts.oraclePriceLots
+}
+
+func sideName(side openbook.Side) string {
+	if side == openbook.Bid {
+		return "bid"
+	}
+	return "ask"
+}
+
+func orderTreeName(tree openbook.BookSideOrderTree) string {
+	if tree == openbook.OraclePeggedOrderTree {
+		return "pegged"
+	}
+	return "fixed"
+}
+
+func runMarket(opts *options) error {
+	address, market, err := loadMarket(opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "address\t%s\n", address)
+	fmt.Fprintf(w, "name\t%s\n", market.NameString())
+	fmt.Fprintf(w, "base mint\t%s\n", market.BaseMint)
+	fmt.Fprintf(w, "quote mint\t%s\n", market.QuoteMint)
+	fmt.Fprintf(w, "base decimals\t%d\n", market.BaseDecimals)
+	fmt.Fprintf(w, "quote decimals\t%d\n", market.QuoteDecimals)
+	fmt.Fprintf(w, "base lot size\t%d\n", market.BaseLotSize)
+	fmt.Fprintf(w, "quote lot size\t%d\n", market.QuoteLotSize)
+	fmt.Fprintf(w, "tick size\t%g\n", market.PriceLotsToUi(1))
+	fmt.Fprintf(w, "min size\t%g\n", market.BaseLotsToUi(1))
+	fmt.Fprintf(w, "maker fee\t%d\n", market.MakerFee)
+	fmt.Fprintf(w, "taker fee\t%d\n", market.TakerFee)
+	fmt.Fprintf(w, "bids\t%s\n", market.Bids)
+	fmt.Fprintf(w, "asks\t%s\n", market.Asks)
+	fmt.Fprintf(w, "event heap\t%s\n", market.EventHeap)
+	fmt.Fprintf(w, "oracle a\t%s\n", optionalKey(market.OracleA))
+	fmt.Fprintf(w, "oracle b\t%s\n", optionalKey(market.OracleB))
+	fmt.Fprintf(w, "open orders admin\t%s\n", optionalKey(market.OpenOrdersAdmin))
+	fmt.Fprintf(w, "consume events admin\t%s\n", optionalKey(market.ConsumeEventsAdmin))
+	fmt.Fprintf(w, "close market admin\t%s\n", optionalKey(market.CloseMarketAdmin))
+	fmt.Fprintf(w, "time expiry\t%d\n", market.TimeExpiry)
+	fmt.Fprintf(w, "seq num\t%d\n", market.SeqNum)
+	fmt.Fprintf(w, "fees available\t%d\n", market.FeesAvailable)
+	fmt.Fprintf(w, "base deposit total\t%d\n", market.BaseDepositTotal)
+	fmt.Fprintf(w, "quote deposit total\t%d\n", market.QuoteDepositTotal)
+	return w.Flush()
+}
+
+func optionalKey(option openbook.NonZeroPubkeyOption) string {
+	if !option.IsSome() {
+		return "-"
+	}
+	return option.Key.String()
+}
+
+func runL2(opts *options) error {
+	market, book, err := loadBook(opts)
+	if err != nil {
+		return err
+	}
+
+	nowTs, err := opts.timestamp()
+	if err != nil {
+		return err
+	}
+
+	bids := book.Bids.L2(nowTs, opts.oraclePrice(), opts.depth)
+	asks := book.Asks.L2(nowTs, opts.oraclePrice(), opts.depth)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "bid orders\tbid size\tbid price\task price\task size\task orders\t")
+	for i := 0; i < len(bids) || i < len(asks); i++ {
+		bidColumns := "\t\t\t"
+		if i < len(bids) {
+			bidColumns = fmt.Sprintf("%d\t%g\t%g\t", bids[i].Orders,
+				market.BaseLotsToUi(bids[i].BaseLots), market.PriceLotsToUi(bids[i].PriceLots))
+		}
+		askColumns := "\t\t\t"
+		if i < len(asks) {
+			askColumns = fmt.Sprintf("%g\t%g\t%d\t", market.PriceLotsToUi(asks[i].PriceLots),
+				market.BaseLotsToUi(asks[i].BaseLots), asks[i].Orders)
+		}
+		fmt.Fprintln(w, bidColumns+askColumns)
+	}
+	return w.Flush()
+}
+
+func runL3(opts *options) error {
+	market, book, err := loadBook(opts)
+	if err != nil {
+		return err
+	}
+	nowTs, err := opts.timestamp()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "side\ttree\tprice\tsize\towner\tslot\tclient id\texpiry\tstate")
+	for _, side := range []openbook.Side{openbook.Ask, openbook.Bid} {
+		for _, order := range book.BookSide(side).L3(nowTs, opts.oraclePrice()) {
+			expiry := "-"
+			if order.TimeInForce > 0 {
+				expiry = fmt.Sprint(order.Timestamp + uint64(order.TimeInForce))
+			}
+			state := "valid"
+			if order.State != openbook.Valid {
+				state = "invalid"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%g\t%g\t%s\t%d\t%d\t%s\t%s\n",
+				sideName(side), orderTreeName(order.OrderTree),
+				market.PriceLotsToUi(order.PriceLots), market.BaseLotsToUi(order.Quantity),
+				order.Owner, order.OwnerSlot, order.ClientOrderID, expiry, state)
+		}
+	}
+	return w.Flush()
+}
+
+func runEvents(opts *options) error {
+	_, market, err := loadMarket(opts)
+	if err != nil {
+		return err
+	}
+	eventHeap, err := loadEventHeap(opts.eventHeapPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d pending events, seq num %d\n", eventHeap.Len(), eventHeap.Header.SeqNum)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "slot\ttype\ttimestamp\tside\tprice\tsize\tmaker/owner\ttaker")
+	for _, item := range eventHeap.Items() {
+		switch item.Event.Type() {
+		case openbook.FillEventType:
+			fill, err := item.Event.Fill()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%d\tfill\t%d\t%s\t%g\t%g\t%s\t%s\n",
+				item.Slot, fill.Timestamp, sideName(openbook.Side(fill.TakerSide)),
+				market.PriceLotsToUi(fill.Price), market.BaseLotsToUi(fill.Quantity),
+				fill.Maker, fill.Taker)
+		case openbook.OutEventType:
+			out, err := item.Event.Out()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%d\tout\t%d\t%s\t\t%g\t%s\t\n",
+				item.Slot, out.Timestamp, sideName(openbook.Side(out.Side)),
+				market.BaseLotsToUi(out.Quantity), out.Owner)
+		default:
+			fmt.Fprintf(w, "%d\tunknown(%d)\t\t\t\t\t\t\n", item.Slot, item.Event.EventType)
+		}
+	}
+	return w.Flush()
+}
+
+func runQuote(opts *options) error {
+	address, market, err := loadMarket(opts)
+	if err != nil {
+		return err
+	}
+	if opts.inputMint == "" {
+		return errors.New("-input-mint is required")
+	}
+	inputMint, err := solana.PublicKeyFromBase58(opts.inputMint)
+	if err != nil {
+		return err
+	}
+
+	accounts := make(map[solana.PublicKey][]byte)
+	for key, path := range map[solana.PublicKey]string{
+		market.Bids:      opts.bidsPath,
+		market.Asks:      opts.asksPath,
+		market.EventHeap: opts.eventHeapPath,
+	} {
+		if path == "" {
+			return errors.New("-bids, -asks and -event-heap are required")
+		}
+		_, data, err := openbook.LoadAccountFile(path)
+		if err != nil {
+			return err
+		}
+		accounts[key] = data
+	}
+
+	// Account dumps have no matching clock unless one is given with -clock
+	nowTs, err := opts.timestamp()
+	if err != nil {
+		return err
+	}
+	obm := openbook.NewOpenBookMarket(address, market)
+	obm.OverrideTimestamp(&nowTs)
+	obm.SetOraclePriceLots(opts.oraclePrice())
+	if err := obm.Update(accounts); err != nil {
+		return err
+	}
+
+	outputMint := market.QuoteMint
+	if inputMint == market.QuoteMint {
+		outputMint = market.BaseMint
+	}
+	quote, take, err := obm.QuoteWithTrace(&openbook.QuoteParams{
+		InAmount:   opts.amount,
+		InputMint:  inputMint,
+		OutputMint: outputMint,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "input mint\t%s\n", inputMint)
+	fmt.Fprintf(w, "output mint\t%s\n", outputMint)
+	fmt.Fprintf(w, "in amount\t%d\n", quote.InAmount)
+	fmt.Fprintf(w, "out amount\t%d\n", quote.OutAmount)
+	fmt.Fprintf(w, "fee amount\t%d\n", quote.FeeAmount)
+	fmt.Fprintf(w, "fee mint\t%s\n", quote.FeeMint)
+	fmt.Fprintf(w, "not enough liquidity\t%t\n", quote.NotEnoughLiquidity)
+	fmt.Fprintf(w, "stop reason\t%s\n", take.StopReason)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if !opts.trace {
+		return nil
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "step\ttree\tkey\towner\tprice lots\tbase lots\tquote lots\tmaker rebate")
+	for _, step := range take.Trace {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\n",
+			step.Kind,
+			orderTreeName(step.Handle.OrderTree),
+			step.Key.String(),
+			step.Owner,
+			step.PriceLots,
+			step.BaseLots,
+			step.QuoteLots,
+			step.MakerRebate,
+		)
+	}
+	return w.Flush()
+}
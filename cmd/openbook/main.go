@@ -0,0 +1,86 @@
+// Command openbook inspects an OpenBook v2 market offline from account dumps,
+// as written by `solana account <address> --output json`.
+//
+// Orders are expired at the current time, or at the unix timestamp of a Clock
+// sysvar dump given with -clock. -now overrides both to replay a dump at a
+// given time.
+//
+// Usage:
+//
+//	openbook market -market market.json
+//	openbook l2     -market market.json -bids bids.json -asks asks.json [-clock clock.json] [-depth 20]
+//	openbook l3     -market market.json -bids bids.json -asks asks.json [-clock clock.json]
+//	openbook events -market market.json -event-heap event_heap.json
+//	openbook quote  -market market.json -bids bids.json -asks asks.json -event-heap event_heap.json -input-mint <mint> -amount <native> [-clock clock.json]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+type options struct {
+	marketPath    string
+	bidsPath      string
+	asksPath      string
+	eventHeapPath string
+	clockPath     string
+
+	nowTs           uint64
+	oraclePriceLots int64
+	depth           int
+
+	inputMint string
+	amount    uint64
+	trace     bool
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	opts := options{}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.StringVar(&opts.marketPath, "market", "", "market account dump")
+	flags.StringVar(&opts.bidsPath, "bids", "", "bids account dump")
+	flags.StringVar(&opts.asksPath, "asks", "", "asks account dump")
+	flags.StringVar(&opts.eventHeapPath, "event-heap", "", "event heap account dump")
+	flags.StringVar(&opts.clockPath, "clock", "", "clock sysvar dump whose unix timestamp expires orders, instead of the current time")
+	flags.Uint64Var(&opts.nowTs, "now", 0, "unix timestamp used to expire orders, overrides -clock and the current time")
+	flags.Int64Var(&opts.oraclePriceLots, "oracle-price-lots", 0, "oracle price in lots used to price pegged orders, 0 ignores them")
+	flags.IntVar(&opts.depth, "depth", 20, "number of l2 levels per side, 0 for all")
+	flags.StringVar(&opts.inputMint, "input-mint", "", "mint of the quoted input amount")
+	flags.Uint64Var(&opts.amount, "amount", 0, "quoted input amount in native units")
+	flags.BoolVar(&opts.trace, "trace", false, "list every order the quote visited and why it stopped")
+	flags.Parse(os.Args[2:])
+
+	var err error
+	switch command {
+	case "market":
+		err = runMarket(&opts)
+	case "l2":
+		err = runL2(&opts)
+	case "l3":
+		err = runL3(&opts)
+	case "events":
+		err = runEvents(&opts)
+	case "quote":
+		err = runQuote(&opts)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: openbook <market|l2|l3|events|quote> [flags]")
+}
@@ -0,0 +1,124 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// Accounts in a legacy transaction are capped by its size; market, event
+	// heap, admin and fee payer leave room for about this many OpenOrders.
+	DEFAULT_CRANK_MAX_ACCOUNTS = 20
+	// The program consumes at most this many events per instruction, whatever
+	// the limit it is given.
+	MAX_CRANK_EVENTS         = 8
+	DEFAULT_CRANK_MAX_EVENTS = MAX_CRANK_EVENTS
+)
+
+type CrankConfig struct {
+	// Maximum number of OpenOrders accounts passed to a single instruction
+	MaxAccounts int
+	// Maximum number of events consumed by a single instruction, at most
+	// MAX_CRANK_EVENTS
+	MaxEvents int
+}
+
+// CrankBatch is a set of pending events that can be consumed by one instruction.
+type CrankBatch struct {
+	// Heap slots of the events in consumption order
+	Slots []uint64
+	// OpenOrders accounts needed as remaining accounts, in first use order
+	Accounts []solana.PublicKey
+	// Prefix is true when the batch is exactly the front of the heap once the
+	// previous batches have landed, so a plain consume_events can be used.
+	Prefix bool
+}
+
+// PlanCrank groups the pending events of the heap by the OpenOrders account
+// they touch and splits them into batches that respect the account and event
+// limits of config. Batches are meant to be sent in the returned order.
+func PlanCrank(eventHeap *EventHeap, config CrankConfig) ([]CrankBatch, error) {
+	if config.MaxAccounts <= 0 || config.MaxEvents <= 0 {
+		return nil, errors.New("crank limits must be greater than 0")
+	}
+	if config.MaxEvents > MAX_CRANK_EVENTS {
+		return nil, fmt.Errorf("the program consumes at most %d events per instruction", MAX_CRANK_EVENTS)
+	}
+
+	type pendingEvent struct {
+		slot    uint16
+		account solana.PublicKey
+	}
+
+	items := eventHeap.Items()
+	pending := make([]pendingEvent, 0, len(items))
+	for _, item := range items {
+		account, err := item.Event.OpenOrdersAccount()
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, pendingEvent{slot: item.Slot, account: account})
+	}
+
+	batches := make([]CrankBatch, 0)
+	for len(pending) > 0 {
+		batch := CrankBatch{Prefix: true}
+		accounts := make(map[solana.PublicKey]bool)
+		remaining := make([]pendingEvent, 0)
+
+		for _, event := range pending {
+			fits := len(batch.Slots) < config.MaxEvents &&
+				(accounts[event.account] || len(accounts) < config.MaxAccounts)
+			if !fits {
+				remaining = append(remaining, event)
+				continue
+			}
+
+			// Taking an event after leaving one behind breaks the run from the front
+			if len(remaining) > 0 {
+				batch.Prefix = false
+			}
+
+			if !accounts[event.account] {
+				accounts[event.account] = true
+				batch.Accounts = append(batch.Accounts, event.account)
+			}
+			batch.Slots = append(batch.Slots, uint64(event.slot))
+		}
+
+		batches = append(batches, batch)
+		pending = remaining
+	}
+
+	return batches, nil
+}
+
+// Instruction builds the consume_events or consume_given_events instruction
+// for the batch.
+func (b *CrankBatch) Instruction(marketAddress solana.PublicKey, market *Market) (solana.Instruction, error) {
+	if b.Prefix {
+		return NewConsumeEventsInstruction(marketAddress, market, uint64(len(b.Slots)), b.Accounts)
+	}
+	return NewConsumeGivenEventsInstruction(marketAddress, market, b.Slots, b.Accounts)
+}
+
+// CrankInstructions plans the crank for the market's current event heap and
+// returns one instruction per batch.
+func (obm *OpenBookMarket) CrankInstructions(config CrankConfig) ([]solana.Instruction, error) {
+	batches, err := PlanCrank(&obm.eventHeap, config)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions := make([]solana.Instruction, 0, len(batches))
+	for i := range batches {
+		instruction, err := batches[i].Instruction(obm.key, &obm.market)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, instruction)
+	}
+	return instructions, nil
+}
@@ -0,0 +1,32 @@
+package openbookdexgolang
+
+import "testing"
+
+func TestPlanCrankBatchesByMaxEvents(t *testing.T) {
+	events := make([]AnyEvent, 0, 20)
+	for i := 0; i < 20; i++ {
+		events = append(events, newTestFill(t, 100, 1, 1))
+	}
+
+	batches, err := PlanCrank(newTestEventHeap(20, events...), CrankConfig{MaxAccounts: DEFAULT_CRANK_MAX_ACCOUNTS, MaxEvents: DEFAULT_CRANK_MAX_EVENTS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := make([]int, 0, len(batches))
+	for _, batch := range batches {
+		sizes = append(sizes, len(batch.Slots))
+		if !batch.Prefix {
+			t.Fatalf("batch %v is not a prefix of the heap", batch.Slots)
+		}
+	}
+	if len(sizes) != 3 || sizes[0] != 8 || sizes[1] != 8 || sizes[2] != 4 {
+		t.Fatalf("batches of %v events, expected [8 8 4]", sizes)
+	}
+}
+
+func TestPlanCrankRejectsMoreEventsThanTheProgramConsumes(t *testing.T) {
+	eventHeap := newTestEventHeap(1, newTestFill(t, 100, 1, 1))
+	if _, err := PlanCrank(eventHeap, CrankConfig{MaxAccounts: 1, MaxEvents: MAX_CRANK_EVENTS + 1}); err == nil {
+		t.Fatal("expected an error for more than MAX_CRANK_EVENTS events")
+	}
+}
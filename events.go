@@ -0,0 +1,141 @@
+package openbookdexgolang
+
+import (
+	"errors"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+type EventType uint8
+
+const (
+	FillEventType EventType = iota
+	OutEventType
+)
+
+type FillEvent struct {
+	EventType uint8
+	// Side from the taker's point of view
+	TakerSide uint8
+	// 1 if the maker order was fully filled
+	MakerOut  uint8
+	MakerSlot uint8
+	Padding   [4]byte
+	Timestamp uint64
+	// Market sequence number at the time of the fill
+	MarketSeqNum uint64
+
+	Maker          solana.PublicKey
+	MakerTimestamp uint64
+
+	Taker              solana.PublicKey
+	TakerClientOrderID uint64
+
+	Price    int64
+	PegLimit int64
+	// Number of base lots
+	Quantity           int64
+	MakerClientOrderID uint64
+	Reserved           [8]byte
+}
+
+type OutEvent struct {
+	EventType uint8
+	Side      uint8
+	OwnerSlot uint8
+	Padding0  [5]byte
+	Timestamp uint64
+	SeqNum    uint64
+	Owner     solana.PublicKey
+	Quantity  int64
+	Padding1  [80]byte
+}
+
+// EventHeapItem is a pending event together with the heap slot it is stored in.
+// The slot is what consume_given_events expects.
+type EventHeapItem struct {
+	Slot  uint16
+	Event *AnyEvent
+}
+
+func (e *AnyEvent) Type() EventType {
+	return EventType(e.EventType)
+}
+
+func (e *AnyEvent) bytes() []byte {
+	data := make([]byte, 0, 1+len(e.Padding))
+	data = append(data, e.EventType)
+	return append(data, e.Padding[:]...)
+}
+
+// Fill decodes the event as a FillEvent.
+func (e *AnyEvent) Fill() (*FillEvent, error) {
+	if e.Type() != FillEventType {
+		return nil, errors.New("event is not a fill event")
+	}
+	var fill FillEvent
+	if err := bin.NewBorshDecoder(e.bytes()).Decode(&fill); err != nil {
+		return nil, err
+	}
+	return &fill, nil
+}
+
+// Out decodes the event as an OutEvent.
+func (e *AnyEvent) Out() (*OutEvent, error) {
+	if e.Type() != OutEventType {
+		return nil, errors.New("event is not an out event")
+	}
+	var out OutEvent
+	if err := bin.NewBorshDecoder(e.bytes()).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// OpenOrdersAccount returns the OpenOrders account consume_events needs in its
+// remaining accounts to process the event: the maker for fills and the owner
+// for outs.
+func (e *AnyEvent) OpenOrdersAccount() (solana.PublicKey, error) {
+	switch e.Type() {
+	case FillEventType:
+		fill, err := e.Fill()
+		if err != nil {
+			return solana.PublicKey{}, err
+		}
+		return fill.Maker, nil
+	case OutEventType:
+		out, err := e.Out()
+		if err != nil {
+			return solana.PublicKey{}, err
+		}
+		return out.Owner, nil
+	default:
+		return solana.PublicKey{}, errors.New("unknown event type")
+	}
+}
+
+func (eh *EventHeap) Len() int {
+	return int(eh.Header.Count)
+}
+
+// Items returns the pending events in the order the program consumes them,
+// starting at the used list head.
+func (eh *EventHeap) Items() []EventHeapItem {
+	count := min(eh.Len(), MAX_NUM_EVENTS)
+	items := make([]EventHeapItem, 0, count)
+	var visited [MAX_NUM_EVENTS]bool
+	slot := eh.Header.UsedHead
+	for i := 0; i < count; i++ {
+		// A corrupt heap could point outside of the node array or back to
+		// an event already listed
+		if int(slot) >= MAX_NUM_EVENTS || visited[slot] {
+			break
+		}
+		visited[slot] = true
+		node := &eh.Nodes[slot]
+		items = append(items, EventHeapItem{Slot: slot, Event: &node.Event})
+		slot = node.Next
+	}
+	return items
+}
@@ -0,0 +1,5 @@
+package openbookdexgolang
+
+// Internals used by the tests of package openbookdexgolang_test, which can
+// import rpctest
+var MarketDiscriminator = marketDiscriminator
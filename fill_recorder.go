@@ -0,0 +1,188 @@
+package openbookdexgolang
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+// RecordedFill is a fill event as written to a FillSink.
+type RecordedFill struct {
+	Market solana.PublicKey `json:"market"`
+	// Slot of the snapshot the fill was first seen in
+	Slot           uint64           `json:"slot"`
+	Timestamp      uint64           `json:"timestamp"`
+	MarketSeqNum   uint64           `json:"marketSeqNum"`
+	TakerSide      Side             `json:"takerSide"`
+	MakerOut       bool             `json:"makerOut"`
+	Maker          solana.PublicKey `json:"maker"`
+	MakerSlot      uint8            `json:"makerSlot"`
+	MakerTimestamp uint64           `json:"makerTimestamp"`
+	Taker          solana.PublicKey `json:"taker"`
+	// Price in lots
+	Price              int64  `json:"price"`
+	PegLimit           int64  `json:"pegLimit"`
+	Quantity           int64  `json:"quantity"`
+	TakerClientOrderID uint64 `json:"takerClientOrderId"`
+	MakerClientOrderID uint64 `json:"makerClientOrderId"`
+}
+
+// FillSink receives every fill exactly once, in the order the fills were
+// pushed to the event heap.
+type FillSink interface {
+	WriteFill(fill *RecordedFill) error
+}
+
+type RecordResult struct {
+	// Number of fills written to the sink
+	Fills int
+	// Number of events pushed and consumed between two snapshots that were
+	// never observed
+	MissedEvents uint64
+	// Snapshot was older than the previous one
+	Reorg bool
+}
+
+// FillRecorder turns successive EventHeap snapshots of one market into a tape
+// of fills.
+//
+// Events are identified by their heap sequence number, EventHeapHeader.SeqNum
+// at the time they were pushed. Events are not stored with it, so it is
+// inferred: events still pending from the previous snapshot keep theirs, and
+// the events pushed since are numbered back from the header's SeqNum. The
+// numbers are exact as long as the events pushed between two snapshots are
+// not consumed out of order with consume_given_events. Every event below the
+// next sequence number to emit was written, which survives re-orgs replaying
+// the same events and heap slots being reused as the heap wraps.
+type FillRecorder struct {
+	market solana.PublicKey
+	sink   FillSink
+
+	initialized bool
+	lastSlot    uint64
+	lastSeqNum  uint64
+	// Events in the previous snapshot
+	previous []sequencedEvent
+	// Events with a lower sequence number were emitted
+	nextSeqNum uint64
+}
+
+// sequencedEvent is a pending event with its heap sequence number.
+type sequencedEvent struct {
+	seqNum uint64
+	event  AnyEvent
+}
+
+func NewFillRecorder(market solana.PublicKey, sink FillSink) *FillRecorder {
+	return &FillRecorder{
+		market: market,
+		sink:   sink,
+	}
+}
+
+// Record feeds a snapshot of the event heap taken at slot and writes the fills
+// not written for earlier snapshots to the sink. When the sink fails, the
+// fills from the failed one on are written again by the next Record.
+func (r *FillRecorder) Record(slot uint64, eventHeap *EventHeap) (RecordResult, error) {
+	var result RecordResult
+	if r.initialized && (slot < r.lastSlot || eventHeap.Header.SeqNum < r.lastSeqNum) {
+		result.Reorg = true
+	}
+
+	events, newEvents := r.sequence(eventHeap, result.Reorg)
+	for i := range events {
+		event := &events[i]
+		if event.seqNum < r.nextSeqNum {
+			continue
+		}
+
+		if event.event.Type() == FillEventType {
+			fill, err := event.event.Fill()
+			if err != nil {
+				return result, err
+			}
+			if err := r.sink.WriteFill(r.recordedFill(slot, fill)); err != nil {
+				return result, err
+			}
+			result.Fills++
+		}
+		r.remember(event.seqNum)
+	}
+
+	// Everything pushed since the last snapshot but not found in this one was
+	// consumed before we could see it
+	if r.initialized && !result.Reorg {
+		pushed := eventHeap.Header.SeqNum - r.lastSeqNum
+		if pushed > newEvents {
+			result.MissedEvents = pushed - newEvents
+		}
+	}
+
+	r.initialized = true
+	r.lastSlot = slot
+	r.lastSeqNum = eventHeap.Header.SeqNum
+	r.previous = events
+	return result, nil
+}
+
+// sequence returns the pending events with their sequence numbers, and the
+// number of them pushed since the previous snapshot.
+func (r *FillRecorder) sequence(eventHeap *EventHeap, reorg bool) ([]sequencedEvent, uint64) {
+	items := eventHeap.Items()
+	events := make([]sequencedEvent, len(items))
+	for i, item := range items {
+		events[i].event = *item.Event
+	}
+
+	// Pending events are kept in the order they were pushed, so the ones still
+	// there from the previous snapshot come first. Without a previous snapshot
+	// to follow, the events are assumed to have been pushed one after the other.
+	matched := 0
+	if r.initialized && !reorg {
+		previous := 0
+		for ; matched < len(events); matched++ {
+			for previous < len(r.previous) && r.previous[previous].event != events[matched].event {
+				previous++
+			}
+			if previous == len(r.previous) {
+				break
+			}
+			events[matched].seqNum = r.previous[previous].seqNum
+			previous++
+		}
+	}
+
+	// The last pushed event is numbered SeqNum - 1
+	for i := matched; i < len(events); i++ {
+		back := uint64(len(events) - i)
+		if back <= eventHeap.Header.SeqNum {
+			events[i].seqNum = eventHeap.Header.SeqNum - back
+		}
+	}
+	return events, uint64(len(events) - matched)
+}
+
+// remember marks every event up to seqNum as emitted.
+func (r *FillRecorder) remember(seqNum uint64) {
+	if seqNum >= r.nextSeqNum {
+		r.nextSeqNum = seqNum + 1
+	}
+}
+
+func (r *FillRecorder) recordedFill(slot uint64, fill *FillEvent) *RecordedFill {
+	return &RecordedFill{
+		Market:             r.market,
+		Slot:               slot,
+		Timestamp:          fill.Timestamp,
+		MarketSeqNum:       fill.MarketSeqNum,
+		TakerSide:          Side(fill.TakerSide),
+		MakerOut:           fill.MakerOut == 1,
+		Maker:              fill.Maker,
+		MakerSlot:          fill.MakerSlot,
+		MakerTimestamp:     fill.MakerTimestamp,
+		Taker:              fill.Taker,
+		Price:              fill.Price,
+		PegLimit:           fill.PegLimit,
+		Quantity:           fill.Quantity,
+		TakerClientOrderID: fill.TakerClientOrderID,
+		MakerClientOrderID: fill.MakerClientOrderID,
+	}
+}
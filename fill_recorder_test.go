@@ -0,0 +1,172 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"testing"
+)
+
+// memorySink keeps the fills written, failing the writes while failures is
+// positive.
+type memorySink struct {
+	fills    []RecordedFill
+	failures int
+}
+
+func (s *memorySink) WriteFill(fill *RecordedFill) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.fills = append(s.fills, *fill)
+	return nil
+}
+
+func (s *memorySink) prices() []int64 {
+	prices := make([]int64, 0, len(s.fills))
+	for _, fill := range s.fills {
+		prices = append(prices, fill.Price)
+	}
+	return prices
+}
+
+func expectPrices(t *testing.T, sink *memorySink, expected ...int64) {
+	t.Helper()
+	prices := sink.prices()
+	if len(prices) != len(expected) {
+		t.Fatalf("fills at %v, expected %v", prices, expected)
+	}
+	for i := range prices {
+		if prices[i] != expected[i] {
+			t.Fatalf("fills at %v, expected %v", prices, expected)
+		}
+	}
+}
+
+func record(t *testing.T, recorder *FillRecorder, slot uint64, eventHeap *EventHeap) RecordResult {
+	t.Helper()
+	result, err := recorder.Record(slot, eventHeap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestFillRecorderEmitsNewFills(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	a, b, c := newTestFill(t, 100, 1, 1), newTestFill(t, 101, 1, 1), newTestFill(t, 102, 1, 2)
+	out := newTestOut(t, 1)
+
+	record(t, recorder, 10, newTestEventHeap(3, a, out, b))
+	expectPrices(t, sink, 100, 101)
+
+	// a was consumed, c pushed
+	result := record(t, recorder, 11, newTestEventHeap(4, out, b, c))
+	expectPrices(t, sink, 100, 101, 102)
+	if result.Fills != 1 || result.MissedEvents != 0 || result.Reorg {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestFillRecorderKeepsIdenticalFills(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	a := newTestFill(t, 100, 1, 1)
+
+	record(t, recorder, 10, newTestEventHeap(1, a))
+	// The same fill again, pushed in a later slot
+	record(t, recorder, 11, newTestEventHeap(2, a, a))
+	expectPrices(t, sink, 100, 100)
+
+	// The first one is consumed, the second one was already written
+	record(t, recorder, 12, newTestEventHeap(2, a))
+	expectPrices(t, sink, 100, 100)
+}
+
+func TestFillRecorderRetriesAfterSinkError(t *testing.T) {
+	sink := &memorySink{failures: 1}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	eventHeap := newTestEventHeap(2, newTestFill(t, 100, 1, 1), newTestFill(t, 101, 1, 1))
+
+	if _, err := recorder.Record(10, eventHeap); err == nil {
+		t.Fatal("expected the sink error")
+	}
+	expectPrices(t, sink)
+
+	result := record(t, recorder, 10, eventHeap)
+	expectPrices(t, sink, 100, 101)
+	if result.Fills != 2 {
+		t.Fatalf("%d fills written, expected 2", result.Fills)
+	}
+
+	// Nothing left to write
+	record(t, recorder, 11, eventHeap)
+	expectPrices(t, sink, 100, 101)
+}
+
+func TestFillRecorderRetriesFromFailedFill(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	a, b, c := newTestFill(t, 100, 1, 1), newTestFill(t, 101, 1, 1), newTestFill(t, 102, 1, 1)
+	record(t, recorder, 10, newTestEventHeap(1, a))
+
+	// b is written, c fails
+	failing := &failAfterSink{sink: sink, writes: 1}
+	recorder.sink = failing
+	if _, err := recorder.Record(11, newTestEventHeap(3, a, b, c)); err == nil {
+		t.Fatal("expected the sink error")
+	}
+	expectPrices(t, sink, 100, 101)
+
+	recorder.sink = sink
+	record(t, recorder, 12, newTestEventHeap(3, b, c))
+	expectPrices(t, sink, 100, 101, 102)
+}
+
+// failAfterSink writes to sink until writes is exhausted, then fails.
+type failAfterSink struct {
+	sink   *memorySink
+	writes int
+}
+
+func (s *failAfterSink) WriteFill(fill *RecordedFill) error {
+	if s.writes == 0 {
+		return errors.New("sink unavailable")
+	}
+	s.writes--
+	return s.sink.WriteFill(fill)
+}
+
+func TestFillRecorderReorgReplay(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	a, b, c, d := newTestFill(t, 100, 1, 1), newTestFill(t, 101, 1, 1), newTestFill(t, 102, 1, 2), newTestFill(t, 103, 1, 3)
+
+	record(t, recorder, 10, newTestEventHeap(2, a, b))
+	record(t, recorder, 12, newTestEventHeap(3, a, b, c))
+
+	// Back to slot 11, before c was pushed
+	result := record(t, recorder, 11, newTestEventHeap(2, a, b))
+	if !result.Reorg || result.Fills != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// c is pushed again, then d
+	record(t, recorder, 12, newTestEventHeap(3, a, b, c))
+	record(t, recorder, 13, newTestEventHeap(4, a, b, c, d))
+	expectPrices(t, sink, 100, 101, 102, 103)
+}
+
+func TestFillRecorderMissedEvents(t *testing.T) {
+	sink := &memorySink{}
+	recorder := NewFillRecorder(testMarketKey, sink)
+	a, c := newTestFill(t, 100, 1, 1), newTestFill(t, 102, 1, 2)
+
+	record(t, recorder, 10, newTestEventHeap(1, a))
+	// Another fill was pushed and consumed with a before this snapshot
+	result := record(t, recorder, 11, newTestEventHeap(3, c))
+	if result.MissedEvents != 1 {
+		t.Fatalf("%d missed events, expected 1", result.MissedEvents)
+	}
+	expectPrices(t, sink, 100, 102)
+}
@@ -0,0 +1,48 @@
+package openbookdexgolang
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// JSONLFileSink appends fills to a file, one JSON object per line.
+type JSONLFileSink struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewJSONLFileSink opens path for appending, creating it if needed.
+func NewJSONLFileSink(path string) (*JSONLFileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &JSONLFileSink{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+func (s *JSONLFileSink) WriteFill(fill *RecordedFill) error {
+	return s.encoder.Encode(fill)
+}
+
+// Flush writes buffered fills to the file and syncs it to disk.
+func (s *JSONLFileSink) Flush() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *JSONLFileSink) Close() error {
+	if err := s.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
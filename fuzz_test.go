@@ -0,0 +1,272 @@
+package openbookdexgolang
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Most items a book side can yield: every node of both trees
+const maxBookSideItems = 2 * MAX_ORDERTREE_NODES
+
+func testBookSideData(t testing.TB, side Side, orders ...BookOrder) []byte {
+	t.Helper()
+	builder := NewBookSideBuilder(side)
+	for _, order := range orders {
+		if err := builder.Add(order); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return encodeTestAccount(t, bookSideDiscriminator, builder.Build())
+}
+
+func FuzzDecodeBookSide(f *testing.F) {
+	bids := []BookOrder{bid(100, 1), bid(99, 2), bid(100, 3), {Side: Bid, OraclePegged: true, PriceLots: -2, PegLimit: -1, Quantity: 4, Owner: testOwner}}
+	f.Add(testBookSideData(f, Bid, bids...), uint64(1), int64(100))
+	f.Add(testBookSideData(f, Ask, ask(100, 1), ask(101, 2), ask(101, 3)), uint64(1), int64(100))
+	f.Add(testBookSideData(f, Ask), uint64(0), int64(0))
+
+	// An inner node linking back to itself
+	cyclic := NewBookSideBuilder(Ask)
+	for _, order := range []BookOrder{ask(100, 1), ask(101, 1)} {
+		if err := cyclic.Add(order); err != nil {
+			f.Fatal(err)
+		}
+	}
+	bookSide := cyclic.Build()
+	root := bookSide.Roots[FixedOrderTree].MaybeNode
+	inner := bookSide.Nodes.Nodes[root].innerNode()
+	inner.Children[1] = root
+	node, err := newAnyNode(&inner)
+	if err != nil {
+		f.Fatal(err)
+	}
+	bookSide.Nodes.Nodes[root] = node
+	f.Add(encodeTestAccount(f, bookSideDiscriminator, bookSide), uint64(1), int64(100))
+
+	// Roots pointing to unused or out of range nodes
+	bookSide = &BookSide{}
+	bookSide.Roots[FixedOrderTree] = OrderTreeRoot{MaybeNode: 3, LeafCount: 1}
+	bookSide.Roots[OraclePeggedOrderTree] = OrderTreeRoot{MaybeNode: MAX_ORDERTREE_NODES, LeafCount: 1}
+	f.Add(encodeTestAccount(f, bookSideDiscriminator, bookSide), uint64(1), int64(100))
+
+	f.Add([]byte{}, uint64(0), int64(0))
+	f.Add(bookSideDiscriminator[:], uint64(0), int64(0))
+
+	f.Fuzz(func(t *testing.T, data []byte, nowTs uint64, oraclePriceLots int64) {
+		bookSide, err := DecodeBookSide(data)
+		if err != nil {
+			return
+		}
+
+		iter := bookSide.IterAllIncludingInvalid(nowTs, &oraclePriceLots)
+		count := 0
+		for _, ok := iter.Next(); ok; _, ok = iter.Next() {
+			count++
+			if count > maxBookSideItems {
+				t.Fatal("iteration did not end")
+			}
+		}
+		bookSide.L2(nowTs, &oraclePriceLots, 0)
+		bookSide.L3(nowTs, nil)
+
+		// Validate must catch every tree the iterator would give up on, and
+		// the trees it accepts must be iterated in full
+		report := bookSide.Validate()
+		for i := range bookSide.Roots {
+			root := &bookSide.Roots[i]
+			if reachesOutOfRange(&bookSide.Nodes, root) && report.Valid() {
+				t.Fatalf("tree %d reaches out of range but is valid", i)
+			}
+			if !report.Valid() {
+				continue
+			}
+			leaves := 0
+			iter := bookSide.Nodes.iter(root)
+			for _, ok := iter.Next(); ok; _, ok = iter.Next() {
+				leaves++
+			}
+			if leaves != int(root.LeafCount) {
+				t.Fatalf("tree %d yielded %d leaves, expected %d", i, leaves, root.LeafCount)
+			}
+		}
+	})
+}
+
+// reachesOutOfRange walks the tree under root and reports whether it reaches
+// a handle at or past BumpIndex, a node that is neither inner nor leaf, a node
+// deeper than MAX_ORDERTREE_DEPTH or a node reached before.
+func reachesOutOfRange(nodes *OrderTreeNodes, root *OrderTreeRoot) bool {
+	if root.LeafCount == 0 {
+		return false
+	}
+
+	reached := make(map[NodeHandle]bool)
+	var walk func(handle NodeHandle, depth int) bool
+	walk = func(handle NodeHandle, depth int) bool {
+		if int(handle) >= MAX_ORDERTREE_NODES || handle >= NodeHandle(nodes.BumpIndex) || reached[handle] || depth > MAX_ORDERTREE_DEPTH {
+			return true
+		}
+		reached[handle] = true
+
+		node := &nodes.Nodes[handle]
+		switch NodeTag(node.Tag) {
+		case leafNode:
+			return false
+		case innerNode:
+			children := node.children()
+			return walk(children[0], depth+1) || walk(children[1], depth+1)
+		default:
+			return true
+		}
+	}
+	return walk(root.MaybeNode, 0)
+}
+
+func FuzzDecodeEventHeap(f *testing.F) {
+	eventHeap := newTestEventHeap(3, newTestFill(f, 100, 1, 1), newTestOut(f, 2), newTestFill(f, 101, 3, 2))
+	f.Add(encodeTestAccount(f, eventHeapDiscriminator, eventHeap))
+
+	// A count larger than the heap, a head outside of it and a cycle
+	eventHeap = newTestEventHeap(2, newTestFill(f, 100, 1, 1), newTestOut(f, 2))
+	eventHeap.Header.Count = MAX_NUM_EVENTS + 1
+	eventHeap.Nodes[1].Next = 0
+	f.Add(encodeTestAccount(f, eventHeapDiscriminator, eventHeap))
+	eventHeap.Header.UsedHead = MAX_NUM_EVENTS
+	f.Add(encodeTestAccount(f, eventHeapDiscriminator, eventHeap))
+
+	// An unknown event type
+	eventHeap = newTestEventHeap(1, AnyEvent{EventType: 7})
+	f.Add(encodeTestAccount(f, eventHeapDiscriminator, eventHeap))
+
+	f.Add([]byte{})
+	f.Add(eventHeapDiscriminator[:])
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		eventHeap, err := DecodeEventHeap(data)
+		if err != nil {
+			return
+		}
+
+		items := eventHeap.Items()
+		if len(items) > eventHeap.Len() || len(items) > MAX_NUM_EVENTS {
+			t.Fatalf("%d items for %d events", len(items), eventHeap.Len())
+		}
+		for _, item := range items {
+			item.Event.Fill()
+			item.Event.Out()
+			item.Event.OpenOrdersAccount()
+		}
+		PlanCrank(eventHeap, CrankConfig{MaxAccounts: DEFAULT_CRANK_MAX_ACCOUNTS, MaxEvents: DEFAULT_CRANK_MAX_EVENTS})
+		NewFillRecorder(testMarketKey, &memorySink{}).Record(1, eventHeap)
+	})
+}
+
+func FuzzDecodeMarket(f *testing.F) {
+	f.Add(encodeTestAccount(f, marketDiscriminator, newTestMarketAccount()), uint64(1_000))
+	market := goldenMarketAccount(9, 6, 1_000_000, 1, -100, 1_000)
+	f.Add(encodeTestAccount(f, marketDiscriminator, &market), uint64(1_000_000))
+
+	// Lot sizes and fees the program rejects
+	market.BaseLotSize = 0
+	f.Add(encodeTestAccount(f, marketDiscriminator, &market), uint64(1_000))
+	market.BaseLotSize, market.TakerFee = 1, -FEES_SCALE_FACTOR
+	f.Add(encodeTestAccount(f, marketDiscriminator, &market), uint64(1_000))
+
+	f.Add([]byte{}, uint64(0))
+	f.Add(marketDiscriminator[:], uint64(0))
+
+	f.Fuzz(func(t *testing.T, data []byte, inAmount uint64) {
+		market, err := DecodeMarket(data)
+		if err != nil {
+			return
+		}
+
+		obm := NewOpenBookMarket(testMarketKey, market)
+		obm.GetAccountsToUpdate()
+		market.NameString()
+		for _, inputMint := range []solana.PublicKey{market.BaseMint, market.QuoteMint} {
+			if _, err := obm.Quote(&QuoteParams{InAmount: inAmount, InputMint: inputMint}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+// FuzzBookSideIter places random orders with the builder, so the trees are
+// always valid, and checks that every order is yielded once, best price first.
+func FuzzBookSideIter(f *testing.F) {
+	f.Add(int64(0), uint16(0), int64(100), uint64(5))
+	f.Add(int64(1), uint16(40), int64(100), uint64(5))
+	f.Add(int64(2), uint16(MAX_ORDERTREE_NODES), int64(1_000), uint64(50))
+	f.Add(int64(3), uint16(300), int64(-20), uint64(0))
+
+	f.Fuzz(func(t *testing.T, seed int64, count uint16, oraclePriceLots int64, nowTs uint64) {
+		rng := rand.New(rand.NewSource(seed))
+		side := Bid
+		if seed%2 != 0 {
+			side = Ask
+		}
+
+		// Leaves and the inner nodes joining them share the node array
+		count = min(count, MAX_ORDERTREE_NODES/2)
+		builder := NewBookSideBuilder(side)
+		yielded := make(map[BookSideOrderHandle]bool)
+		expected := 0
+		for i := 0; i < int(count); i++ {
+			order := BookOrder{
+				Side:        side,
+				PriceLots:   1 + rng.Int63n(50),
+				PegLimit:    -1,
+				Quantity:    1 + rng.Int63n(10),
+				Owner:       testOwner,
+				Timestamp:   uint64(rng.Intn(10)),
+				TimeInForce: uint16(rng.Intn(3) * 5),
+			}
+			if rng.Intn(3) == 0 {
+				order.OraclePegged = true
+				order.PriceLots = rng.Int63n(100) - 50
+				if rng.Intn(2) == 0 {
+					order.PegLimit = 1 + rng.Int63n(100)
+				}
+			}
+			if err := builder.Add(order); err != nil {
+				t.Fatal(err)
+			}
+
+			// Pegged orders at a price out of 1..MaxInt64 are skipped
+			price := saturatingAdd(oraclePriceLots, order.PriceLots)
+			if !order.OraclePegged || (price >= 1 && price < math.MaxInt64) {
+				expected++
+			}
+		}
+		bookSide := builder.Build()
+
+		iter := bookSide.IterAllIncludingInvalid(nowTs, &oraclePriceLots)
+		var previous *int64
+		for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+			if yielded[item.Handle] {
+				t.Fatalf("node %d of tree %d yielded twice", item.Handle.Node, item.Handle.OrderTree)
+			}
+			yielded[item.Handle] = true
+			if len(yielded) > expected {
+				t.Fatalf("more than the %d orders yielded", expected)
+			}
+
+			if previous != nil && side.IsPriceBetter(item.PriceLots, *previous) {
+				t.Fatalf("price %d after %d", item.PriceLots, *previous)
+			}
+			price := item.PriceLots
+			previous = &price
+
+			if (item.State == Valid) == item.Node.IsExpired(nowTs) && item.Handle.OrderTree == FixedOrderTree {
+				t.Fatalf("order expiring at %d yielded as %d at %d", item.Node.Timestamp+uint64(item.Node.TimeInForce), item.State, nowTs)
+			}
+		}
+		if len(yielded) != expected {
+			t.Fatalf("%d orders yielded, expected %d", len(yielded), expected)
+		}
+	})
+}
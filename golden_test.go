@@ -0,0 +1,375 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var writeGoldenAccounts = flag.Bool("golden.accounts", false, "write the account dumps of testdata/golden from goldenCases")
+
+// Accounts shared by the golden markets
+var (
+	goldenBaseMint  = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	goldenQuoteMint = solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	goldenMarket    = solana.MustPublicKeyFromBase58("CFSMrBssNG8Ud1edW59jNLnq2cwrQ9uY5cM3wXmqRJj3")
+	goldenBids      = solana.MustPublicKeyFromBase58("Ad5skEiFoaeA27G3UhbpuwnFBCvmuuGEyoiijZhcd5xX")
+	goldenAsks      = solana.MustPublicKeyFromBase58("53v47CBoaKwoM8tSEDN4oNyCc2ZJenDeuhMJTEw7fL2M")
+	goldenEventHeap = solana.MustPublicKeyFromBase58("6SakVBCDDM5jH4tS9pd9EbsGy5sUV9QAMqvjixbrbZAq")
+	goldenMaker     = solana.MustPublicKeyFromBase58("9GgrUPfM4wnvEuo6fMdexHrjNu3TjXygbMbhsozfT6Qj")
+	goldenOracle    = solana.MustPublicKeyFromBase58("8vs9y4UuRDWKA6SLv3M9veeQvAv4P7vDwbQMoJ4wBmxw")
+
+	goldenSysvarOwner = solana.MustPublicKeyFromBase58("Sysvar1111111111111111111111111111111111111")
+)
+
+// goldenCase describes the state dumped to testdata/golden/<name>. Only the
+// account dumps are generated from it, expected.json is worked out by hand
+// from the program's matching rules.
+type goldenCase struct {
+	name   string
+	market Market
+	orders []BookOrder
+	clock  Clock
+	oracle *StubOracle // OracleA of the market, nil for none
+}
+
+func goldenMarketAccount(baseDecimals, quoteDecimals uint8, baseLotSize, quoteLotSize, makerFee, takerFee int64) Market {
+	market := Market{
+		BaseDecimals:  baseDecimals,
+		QuoteDecimals: quoteDecimals,
+		Bids:          goldenBids,
+		Asks:          goldenAsks,
+		EventHeap:     goldenEventHeap,
+		BaseLotSize:   baseLotSize,
+		QuoteLotSize:  quoteLotSize,
+		MakerFee:      makerFee,
+		TakerFee:      takerFee,
+		BaseMint:      goldenBaseMint,
+		QuoteMint:     goldenQuoteMint,
+	}
+	copy(market.Name[:], "SOL-USDC")
+	return market
+}
+
+// goldenPeggedMarketAccount is a market priced by a stub oracle at goldenOracle.
+func goldenPeggedMarketAccount(baseDecimals, quoteDecimals uint8, baseLotSize, quoteLotSize, makerFee, takerFee int64) Market {
+	market := goldenMarketAccount(baseDecimals, quoteDecimals, baseLotSize, quoteLotSize, makerFee, takerFee)
+	market.OracleA = NonZeroPubkeyOption{Key: goldenOracle}
+	market.OracleConfig = OracleConfig{ConfFilter: 0.1, MaxStalenessSlots: 100}
+	return market
+}
+
+func goldenOrder(side Side, priceLots, quantity int64, timestamp uint64, timeInForce uint16) BookOrder {
+	return BookOrder{
+		Side:        side,
+		PriceLots:   priceLots,
+		Quantity:    quantity,
+		Owner:       goldenMaker,
+		Timestamp:   timestamp,
+		TimeInForce: timeInForce,
+	}
+}
+
+// goldenPeggedOrder returns a pegged order at offsetLots from the oracle price,
+// pegLimit is -1 for no limit.
+func goldenPeggedOrder(side Side, offsetLots, pegLimit, quantity int64, timestamp uint64, timeInForce uint16) BookOrder {
+	order := goldenOrder(side, offsetLots, quantity, timestamp, timeInForce)
+	order.OraclePegged = true
+	order.PegLimit = pegLimit
+	return order
+}
+
+var goldenCases = []goldenCase{
+	{
+		// Lots of 0.001 SOL and 0.000001 USDC, 0.1% taker fee, 0.01% maker
+		// rebate. Orders are added in sequence number order, expired orders
+		// were placed at 0 with a time in force of 10.
+		name:   "fixed",
+		market: goldenMarketAccount(9, 6, 1_000_000, 1, -100, 1_000),
+		orders: []BookOrder{
+			goldenOrder(Ask, 20_000, 10, 900, 0),
+			goldenOrder(Ask, 20_100, 5, 900, 0),
+			goldenOrder(Ask, 20_500, 20, 900, 0),
+			goldenOrder(Ask, 19_990, 7, 0, 10),
+			goldenOrder(Ask, 20_000, 4, 950, 0),
+			goldenOrder(Bid, 19_900, 8, 900, 0),
+			goldenOrder(Bid, 19_800, 12, 900, 0),
+			goldenOrder(Bid, 19_950, 50, 0, 10),
+			goldenOrder(Bid, 19_900, 3, 950, 0),
+		},
+		clock: Clock{Slot: 250_000_000, UnixTimestamp: 1_000},
+	},
+	{
+		// Lots of 100 and 10 native units, 0.05% taker fee, positive maker
+		// fee so no rebates
+		name:   "lot_rounding",
+		market: goldenMarketAccount(6, 6, 100, 10, 200, 500),
+		orders: []BookOrder{
+			goldenOrder(Ask, 105, 30, 900, 0),
+			goldenOrder(Ask, 110, 50, 900, 0),
+			goldenOrder(Bid, 95, 40, 900, 0),
+			goldenOrder(Bid, 90, 10, 900, 0),
+		},
+		clock: Clock{Slot: 250_000_000, UnixTimestamp: 1_000},
+	},
+	{
+		// Lots of 100 and 10 native units, 0.05% taker fee, 0.1% maker
+		// rebate, a stub oracle at 10 so 100 lots. Pegged orders beyond their
+		// peg limit, expired or priced below 1 lot are never matched.
+		name:   "pegged",
+		market: goldenPeggedMarketAccount(6, 6, 100, 10, -1_000, 500),
+		orders: []BookOrder{
+			goldenOrder(Ask, 103, 20, 900, 0),
+			goldenPeggedOrder(Ask, 1, -1, 10, 900, 0),
+			goldenPeggedOrder(Ask, -2, 99, 5, 900, 0),
+			goldenPeggedOrder(Ask, 2, -1, 6, 0, 10),
+			goldenOrder(Ask, 99, 7, 0, 10),
+			goldenOrder(Ask, 101, 4, 950, 0),
+			goldenOrder(Bid, 97, 10, 900, 0),
+			goldenPeggedOrder(Bid, -1, -1, 8, 900, 0),
+			goldenPeggedOrder(Bid, 1, 100, 5, 900, 0),
+			goldenPeggedOrder(Bid, -2, 99, 3, 900, 0),
+			goldenOrder(Bid, 100, 50, 0, 10),
+			goldenPeggedOrder(Bid, -150, -1, 2, 900, 0),
+		},
+		clock: Clock{Slot: 250_000_000, UnixTimestamp: 1_000},
+		oracle: &StubOracle{
+			Mint:           goldenBaseMint,
+			Price:          10,
+			LastUpdateTs:   990,
+			LastUpdateSlot: 249_999_990,
+			Deviation:      0.01,
+		},
+	},
+}
+
+type goldenQuote struct {
+	InputMint      string       `json:"inputMint"` // "base" or "quote"
+	InAmount       uint64       `json:"inAmount"`
+	LimitPriceLots *int64       `json:"limitPriceLots"`
+	Expected       goldenResult `json:"expected"`
+	Take           goldenTake   `json:"take"`
+}
+
+type goldenResult struct {
+	InAmount           uint64  `json:"inAmount"`
+	OutAmount          uint64  `json:"outAmount"`
+	FeeAmount          uint64  `json:"feeAmount"`
+	MinOutAmount       *uint64 `json:"minOutAmount"`
+	NotEnoughLiquidity bool    `json:"notEnoughLiquidity"`
+}
+
+type goldenTake struct {
+	BaseLots     int64  `json:"baseLots"`
+	QuoteLots    int64  `json:"quoteLots"`
+	MakerRebates int64  `json:"makerRebates"`
+	StopReason   string `json:"stopReason"`
+}
+
+type goldenExpected struct {
+	L2 struct {
+		Bids []L2Level `json:"bids"`
+		Asks []L2Level `json:"asks"`
+	} `json:"l2"`
+	Quotes []goldenQuote `json:"quotes"`
+}
+
+func TestGolden(t *testing.T) {
+	if *writeGoldenAccounts {
+		for _, c := range goldenCases {
+			writeGoldenCase(t, &c)
+		}
+	}
+
+	for _, c := range goldenCases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := filepath.Join("testdata", "golden", c.name)
+			obm := loadGoldenMarket(t, dir)
+
+			content, err := os.ReadFile(filepath.Join(dir, "expected.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var expected goldenExpected
+			if err := json.Unmarshal(content, &expected); err != nil {
+				t.Fatal(err)
+			}
+
+			book := obm.Orderbook()
+			oraclePriceLots := obm.OraclePriceLots()
+			if bids := book.Bids.L2(obm.Timestamp(), oraclePriceLots, 0); !reflect.DeepEqual(bids, expected.L2.Bids) {
+				t.Errorf("bids %+v, expected %+v", bids, expected.L2.Bids)
+			}
+			if asks := book.Asks.L2(obm.Timestamp(), oraclePriceLots, 0); !reflect.DeepEqual(asks, expected.L2.Asks) {
+				t.Errorf("asks %+v, expected %+v", asks, expected.L2.Asks)
+			}
+
+			for _, q := range expected.Quotes {
+				params := QuoteParams{InAmount: q.InAmount, LimitPriceLots: q.LimitPriceLots}
+				params.InputMint, params.OutputMint = goldenBaseMint, goldenQuoteMint
+				if q.InputMint == "quote" {
+					params.InputMint, params.OutputMint = goldenQuoteMint, goldenBaseMint
+				}
+
+				quote, take, err := obm.QuoteWithTrace(&params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result := goldenResult{
+					InAmount:           quote.InAmount,
+					OutAmount:          quote.OutAmount,
+					FeeAmount:          quote.FeeAmount,
+					MinOutAmount:       quote.MinOutAmount,
+					NotEnoughLiquidity: quote.NotEnoughLiquidity,
+				}
+				if !reflect.DeepEqual(result, q.Expected) || quote.FeeMint != goldenQuoteMint {
+					t.Errorf("%s %d: quote %+v, expected %+v", q.InputMint, q.InAmount, *quote, q.Expected)
+				}
+				actualTake := goldenTake{
+					BaseLots:     take.TotalBaseLotsTaken,
+					QuoteLots:    take.TotalQuoteLotsTaken,
+					MakerRebates: take.MakerRebates,
+					StopReason:   take.StopReason.String(),
+				}
+				if actualTake != q.Take {
+					t.Errorf("%s %d: take %+v, expected %+v", q.InputMint, q.InAmount, actualTake, q.Take)
+				}
+
+				// The lower level entry points agree with the quote
+				side := obm.takerSide(params.InputMint)
+				maxBaseLots, maxQuoteLots := obm.market.MaxBaseLots(), obm.market.MaxQuoteLots()
+				if side == Bid {
+					maxQuoteLots = divCeil(int64(q.InAmount), obm.market.QuoteLotSize)
+				} else {
+					maxBaseLots = divCeil(int64(q.InAmount), obm.market.BaseLotSize)
+				}
+				if q.LimitPriceLots == nil {
+					var oraclePrice *big.Float
+					if oraclePriceLots != nil {
+						oraclePrice = big.NewFloat(float64(*oraclePriceLots*obm.market.QuoteLotSize) / float64(obm.market.BaseLotSize))
+					}
+
+					accounts := make([]solana.PublicKey, 0)
+					baseLots, quoteLots, rebates, notEnoughLiquidity := IterateBook(book, side, maxBaseLots, maxQuoteLots, &obm.market, oraclePriceLots, obm.Timestamp(), &accounts)
+					if baseLots != q.Take.BaseLots || quoteLots != q.Take.QuoteLots || rebates != q.Take.MakerRebates || notEnoughLiquidity != q.Expected.NotEnoughLiquidity {
+						t.Errorf("%s %d: IterateBook %d %d %d %v", q.InputMint, q.InAmount, baseLots, quoteLots, rebates, notEnoughLiquidity)
+					}
+
+					amounts, err := AmountsFromBook(book, side, maxBaseLots, maxQuoteLots, &obm.market, oraclePrice, obm.Timestamp())
+					if err != nil {
+						t.Fatal(err)
+					}
+					if amounts.TotalBaseTakenNative != uint64(q.Take.BaseLots*obm.market.BaseLotSize) ||
+						amounts.TotalQuoteTakenNative != uint64(q.Take.QuoteLots*obm.market.QuoteLotSize) ||
+						amounts.Fee != q.Expected.FeeAmount {
+						t.Errorf("%s %d: AmountsFromBook %+v", q.InputMint, q.InAmount, amounts)
+					}
+				}
+			}
+		})
+	}
+}
+
+// loadGoldenMarket decodes the account dumps of dir into a market.
+func loadGoldenMarket(t *testing.T, dir string) *OpenBookMarket {
+	t.Helper()
+	load := func(name string) (solana.PublicKey, []byte) {
+		address, data, err := LoadAccountFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return address, data
+	}
+
+	marketAddress, marketData := load("market.json")
+	market, err := DecodeMarket(marketData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obm := NewOpenBookMarket(marketAddress, market)
+
+	accounts := make(map[solana.PublicKey][]byte)
+	for _, name := range []string{"bids.json", "asks.json", "event_heap.json", "clock.json"} {
+		address, data := load(name)
+		accounts[address] = data
+	}
+	if err := obm.Update(accounts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pegged orders are priced from the oracle dump like the daemon does
+	if oracles := obm.OracleAccounts(); len(oracles) > 0 {
+		address, data := load("oracle.json")
+		if address != oracles[0] {
+			t.Fatalf("oracle dump of %s, market oracle is %s", address, oracles[0])
+		}
+		accounts[address] = data
+		if err := obm.UpdateOraclePrice(accounts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return obm
+}
+
+func writeGoldenCase(t *testing.T, c *goldenCase) {
+	t.Helper()
+	book, err := BuildOrderbook(c.orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("testdata", "golden", c.name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, address, owner solana.PublicKey, discriminator []byte, v interface{}) {
+		buf := bytes.NewBuffer(nil)
+		buf.Write(discriminator)
+		var err error
+		if discriminator == nil {
+			err = bin.NewBinEncoder(buf).Encode(v)
+		} else {
+			err = bin.NewBorshEncoder(buf).Encode(v)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := map[string]interface{}{
+			"pubkey": address.String(),
+			"account": map[string]interface{}{
+				"lamports":   1_000_000_000,
+				"data":       []string{base64.StdEncoding.EncodeToString(buf.Bytes()), "base64"},
+				"owner":      owner.String(),
+				"executable": false,
+				"rentEpoch":  uint64(18446744073709551615),
+				"space":      buf.Len(),
+			},
+		}
+		content, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), append(content, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("market.json", goldenMarket, OPENBOOK_V2_PROGRAM_ID, marketDiscriminator[:], &c.market)
+	write("bids.json", goldenBids, OPENBOOK_V2_PROGRAM_ID, bookSideDiscriminator[:], book.Bids)
+	write("asks.json", goldenAsks, OPENBOOK_V2_PROGRAM_ID, bookSideDiscriminator[:], book.Asks)
+	write("event_heap.json", goldenEventHeap, OPENBOOK_V2_PROGRAM_ID, eventHeapDiscriminator[:], &EventHeap{})
+	write("clock.json", solana.SysVarClockPubkey, goldenSysvarOwner, nil, &c.clock)
+	if c.oracle != nil {
+		write("oracle.json", goldenOracle, OPENBOOK_V2_PROGRAM_ID, stubOracleDiscriminator[:], c.oracle)
+	}
+}
@@ -0,0 +1,112 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	testMarketKey = solana.PublicKey{1}
+	testBaseMint  = solana.PublicKey{2}
+	testQuoteMint = solana.PublicKey{3}
+	testOwner     = solana.PublicKey{4}
+)
+
+// newTestMarketAccount returns a market without decimals, with lot sizes of 1
+// and no fees, so that native amounts, lots and ui amounts are all equal.
+func newTestMarketAccount() *Market {
+	return &Market{
+		Bids:         solana.PublicKey{5},
+		Asks:         solana.PublicKey{6},
+		EventHeap:    solana.PublicKey{7},
+		BaseMint:     testBaseMint,
+		QuoteMint:    testQuoteMint,
+		BaseLotSize:  1,
+		QuoteLotSize: 1,
+	}
+}
+
+// newTestMarket returns a market quoting the given orders at timestamp 1.
+func newTestMarket(t testing.TB, market *Market, orders ...BookOrder) *OpenBookMarket {
+	t.Helper()
+	book, err := BuildOrderbook(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obm := NewOpenBookMarket(testMarketKey, market)
+	obm.bids = *book.Bids
+	obm.asks = *book.Asks
+	timestamp := uint64(1)
+	obm.OverrideTimestamp(&timestamp)
+	return obm
+}
+
+func bid(priceLots, quantity int64) BookOrder {
+	return BookOrder{Side: Bid, PriceLots: priceLots, Quantity: quantity, Owner: testOwner}
+}
+
+func ask(priceLots, quantity int64) BookOrder {
+	return BookOrder{Side: Ask, PriceLots: priceLots, Quantity: quantity, Owner: testOwner}
+}
+
+// newTestFill returns a fill event of the test owner's taker order.
+func newTestFill(t testing.TB, priceLots, quantity int64, timestamp uint64) AnyEvent {
+	t.Helper()
+	fill := FillEvent{
+		EventType: uint8(FillEventType),
+		Timestamp: timestamp,
+		Maker:     testOwner,
+		Taker:     testOwner,
+		Price:     priceLots,
+		Quantity:  quantity,
+	}
+	return newTestEvent(t, &fill)
+}
+
+func newTestOut(t testing.TB, quantity int64) AnyEvent {
+	t.Helper()
+	return newTestEvent(t, &OutEvent{EventType: uint8(OutEventType), Owner: testOwner, Quantity: quantity})
+}
+
+func newTestEvent(t testing.TB, v interface{}) AnyEvent {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBorshEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	var event AnyEvent
+	event.EventType = buf.Bytes()[0]
+	copy(event.Padding[:], buf.Bytes()[1:])
+	return event
+}
+
+// newTestEventHeap returns a heap holding the events in order, the last one
+// pushed as event seqNum - 1.
+func newTestEventHeap(seqNum uint64, events ...AnyEvent) *EventHeap {
+	eventHeap := &EventHeap{}
+	eventHeap.Header.Count = uint16(len(events))
+	eventHeap.Header.FreeHead = uint16(len(events))
+	eventHeap.Header.SeqNum = seqNum
+	for i, event := range events {
+		eventHeap.Nodes[i].Event = event
+		eventHeap.Nodes[i].Next = uint16(i + 1)
+		eventHeap.Nodes[i].Prev = uint16(i - 1)
+	}
+	return eventHeap
+}
+
+// encodeTestAccount borsh-encodes v behind the discriminator, like the
+// account data the program stores.
+func encodeTestAccount(t testing.TB, discriminator [8]byte, v interface{}) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	buf.Write(discriminator[:])
+	if err := bin.NewBorshEncoder(buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
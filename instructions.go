@@ -0,0 +1,99 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+var OPENBOOK_V2_PROGRAM_ID = solana.MustPublicKeyFromBase58("opnb2LAfJYbRMAHHvqjCwQxanZn7ReEHp1k81EohpZb")
+
+// instructionDiscriminator returns the anchor sighash for a global instruction.
+func instructionDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("global:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+func (o NonZeroPubkeyOption) IsSome() bool {
+	return !o.Key.IsZero()
+}
+
+// consumeEventsAccounts builds the fixed accounts shared by consume_events and
+// consume_given_events followed by the OpenOrders accounts as remaining accounts.
+func consumeEventsAccounts(
+	marketAddress solana.PublicKey,
+	market *Market,
+	openOrdersAccounts []solana.PublicKey,
+) solana.AccountMetaSlice {
+	accounts := make(solana.AccountMetaSlice, 0, 3+len(openOrdersAccounts))
+
+	// Anchor expects the program id in place of an absent optional account
+	if market.ConsumeEventsAdmin.IsSome() {
+		accounts = append(accounts, solana.Meta(market.ConsumeEventsAdmin.Key).SIGNER())
+	} else {
+		accounts = append(accounts, solana.Meta(OPENBOOK_V2_PROGRAM_ID))
+	}
+	accounts = append(accounts,
+		solana.Meta(marketAddress).WRITE(),
+		solana.Meta(market.EventHeap).WRITE(),
+	)
+
+	for _, openOrders := range openOrdersAccounts {
+		accounts = append(accounts, solana.Meta(openOrders).WRITE())
+	}
+	return accounts
+}
+
+// NewConsumeEventsInstruction builds a consume_events instruction processing up
+// to limit events from the front of the heap.
+func NewConsumeEventsInstruction(
+	marketAddress solana.PublicKey,
+	market *Market,
+	limit uint64,
+	openOrdersAccounts []solana.PublicKey,
+) (solana.Instruction, error) {
+	buf := bytes.NewBuffer(nil)
+	encoder := bin.NewBorshEncoder(buf)
+	discriminator := instructionDiscriminator("consume_events")
+	if err := encoder.WriteBytes(discriminator[:], false); err != nil {
+		return nil, err
+	}
+	if err := encoder.WriteUint64(limit, bin.LE); err != nil {
+		return nil, err
+	}
+
+	return solana.NewInstruction(
+		OPENBOOK_V2_PROGRAM_ID,
+		consumeEventsAccounts(marketAddress, market, openOrdersAccounts),
+		buf.Bytes(),
+	), nil
+}
+
+// NewConsumeGivenEventsInstruction builds a consume_given_events instruction
+// processing exactly the events stored in the given heap slots.
+func NewConsumeGivenEventsInstruction(
+	marketAddress solana.PublicKey,
+	market *Market,
+	slots []uint64,
+	openOrdersAccounts []solana.PublicKey,
+) (solana.Instruction, error) {
+	buf := bytes.NewBuffer(nil)
+	encoder := bin.NewBorshEncoder(buf)
+	discriminator := instructionDiscriminator("consume_given_events")
+	if err := encoder.WriteBytes(discriminator[:], false); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(slots); err != nil {
+		return nil, err
+	}
+
+	return solana.NewInstruction(
+		OPENBOOK_V2_PROGRAM_ID,
+		consumeEventsAccounts(marketAddress, market, openOrdersAccounts),
+		buf.Bytes(),
+	), nil
+}
@@ -0,0 +1,511 @@
+package openbookdexgolang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Canonical JSON encoding of the book, quote and market types. Public keys are
+// base58 strings, u128 values decimal strings and padding is left out.
+
+type leafNodeJSON struct {
+	OwnerSlot     uint8            `json:"ownerSlot"`
+	TimeInForce   uint16           `json:"timeInForce"`
+	Key           bin.Uint128      `json:"key"`
+	Owner         solana.PublicKey `json:"owner"`
+	Quantity      int64            `json:"quantity"`
+	Timestamp     uint64           `json:"timestamp"`
+	PegLimit      int64            `json:"pegLimit"`
+	ClientOrderID uint64           `json:"clientOrderId"`
+}
+
+func (ln LeafNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(leafNodeJSON{
+		OwnerSlot:     ln.OwnerSlot,
+		TimeInForce:   ln.TimeInForce,
+		Key:           ln.Key,
+		Owner:         ln.Owner,
+		Quantity:      ln.Quantity,
+		Timestamp:     ln.Timestamp,
+		PegLimit:      ln.PegLimit,
+		ClientOrderID: ln.ClientOrderID,
+	})
+}
+
+func (ln *LeafNode) UnmarshalJSON(data []byte) error {
+	var v leafNodeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*ln = LeafNode{
+		Tag:           uint8(leafNode),
+		OwnerSlot:     v.OwnerSlot,
+		TimeInForce:   v.TimeInForce,
+		Key:           v.Key,
+		Owner:         v.Owner,
+		Quantity:      v.Quantity,
+		Timestamp:     v.Timestamp,
+		PegLimit:      v.PegLimit,
+		ClientOrderID: v.ClientOrderID,
+	}
+	return nil
+}
+
+type innerNodeJSON struct {
+	PrefixLen           uint32        `json:"prefixLen"`
+	Key                 bin.Uint128   `json:"key"`
+	Children            [2]NodeHandle `json:"children"`
+	ChildEarliestExpiry [2]uint64     `json:"childEarliestExpiry"`
+}
+
+// nodeJSON is one used slot of the node array. Exactly one of Inner, Leaf and
+// Next is set, matching Tag.
+type nodeJSON struct {
+	Handle NodeHandle     `json:"handle"`
+	Tag    string         `json:"tag"`
+	Inner  *innerNodeJSON `json:"inner,omitempty"`
+	Leaf   *LeafNode      `json:"leaf,omitempty"`
+	Next   *NodeHandle    `json:"next,omitempty"`
+}
+
+type orderTreeRootJSON struct {
+	MaybeNode NodeHandle `json:"maybeNode"`
+	LeafCount uint32     `json:"leafCount"`
+}
+
+type bookSideJSON struct {
+	OrderTreeType string               `json:"orderTreeType"`
+	Roots         [2]orderTreeRootJSON `json:"roots"`
+	BumpIndex     uint32               `json:"bumpIndex"`
+	FreeListLen   uint32               `json:"freeListLen"`
+	FreeListHead  NodeHandle           `json:"freeListHead"`
+	Nodes         []nodeJSON           `json:"nodes"`
+}
+
+var nodeTagNames = map[NodeTag]string{
+	innerNode:    "inner",
+	leafNode:     "leaf",
+	freeNode:     "free",
+	lastFreeNode: "lastFree",
+}
+
+// MarshalJSON keeps the full tree structure so that the BookSide can be
+// restored exactly. Uninitialized nodes are left out.
+func (b *BookSide) MarshalJSON() ([]byte, error) {
+	v := bookSideJSON{
+		OrderTreeType: "bids",
+		BumpIndex:     b.Nodes.BumpIndex,
+		FreeListLen:   b.Nodes.FreeListLen,
+		FreeListHead:  b.Nodes.FreeListHead,
+		Nodes:         make([]nodeJSON, 0),
+	}
+	if b.Nodes.order_tree_type() == Asks {
+		v.OrderTreeType = "asks"
+	}
+	for i, root := range b.Roots {
+		v.Roots[i] = orderTreeRootJSON{MaybeNode: root.MaybeNode, LeafCount: root.LeafCount}
+	}
+
+	for i := range b.Nodes.Nodes {
+		node := &b.Nodes.Nodes[i]
+		tag := NodeTag(node.Tag)
+		if tag == uninitialized {
+			continue
+		}
+		name, ok := nodeTagNames[tag]
+		if !ok {
+			return nil, fmt.Errorf("node %d has invalid tag %d", i, node.Tag)
+		}
+
+		entry := nodeJSON{Handle: NodeHandle(i), Tag: name}
+		switch tag {
+		case innerNode:
+			var inner InnerNode
+			if err := node.decode(&inner); err != nil {
+				return nil, err
+			}
+			entry.Inner = &innerNodeJSON{
+				PrefixLen:           inner.PrefixLen,
+				Key:                 inner.Key,
+				Children:            inner.Children,
+				ChildEarliestExpiry: inner.ChildEarliestExpiry,
+			}
+		case leafNode:
+			var leaf LeafNode
+			if err := node.decode(&leaf); err != nil {
+				return nil, err
+			}
+			entry.Leaf = &leaf
+		default:
+			var free FreeNode
+			if err := node.decode(&free); err != nil {
+				return nil, err
+			}
+			entry.Next = &free.Next
+		}
+		v.Nodes = append(v.Nodes, entry)
+	}
+
+	return json.Marshal(v)
+}
+
+func (b *BookSide) UnmarshalJSON(data []byte) error {
+	var v bookSideJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var bookSide BookSide
+	switch v.OrderTreeType {
+	case "bids":
+		bookSide.Nodes.OrderTreeType = uint8(Bids)
+	case "asks":
+		bookSide.Nodes.OrderTreeType = uint8(Asks)
+	default:
+		return fmt.Errorf("invalid order tree type %q", v.OrderTreeType)
+	}
+	for i, root := range v.Roots {
+		bookSide.Roots[i] = OrderTreeRoot{MaybeNode: root.MaybeNode, LeafCount: root.LeafCount}
+	}
+	bookSide.Nodes.BumpIndex = v.BumpIndex
+	bookSide.Nodes.FreeListLen = v.FreeListLen
+	bookSide.Nodes.FreeListHead = v.FreeListHead
+
+	for _, entry := range v.Nodes {
+		if int(entry.Handle) >= MAX_ORDERTREE_NODES {
+			return fmt.Errorf("node handle %d out of range", entry.Handle)
+		}
+
+		var node AnyNode
+		var err error
+		switch {
+		case entry.Tag == "inner" && entry.Inner != nil:
+			node, err = newAnyNode(&InnerNode{
+				Tag:                 uint8(innerNode),
+				PrefixLen:           entry.Inner.PrefixLen,
+				Key:                 entry.Inner.Key,
+				Children:            entry.Inner.Children,
+				ChildEarliestExpiry: entry.Inner.ChildEarliestExpiry,
+			})
+		case entry.Tag == "leaf" && entry.Leaf != nil:
+			node, err = newAnyNode(entry.Leaf)
+		case entry.Tag == "free" && entry.Next != nil:
+			node, err = newAnyNode(&FreeNode{Tag: uint8(freeNode), Next: *entry.Next})
+		case entry.Tag == "lastFree" && entry.Next != nil:
+			node, err = newAnyNode(&FreeNode{Tag: uint8(lastFreeNode), Next: *entry.Next})
+		default:
+			return fmt.Errorf("node %d has invalid tag %q or is missing its content", entry.Handle, entry.Tag)
+		}
+		if err != nil {
+			return err
+		}
+		bookSide.Nodes.Nodes[entry.Handle] = node
+	}
+
+	*b = bookSide
+	return nil
+}
+
+type orderbookJSON struct {
+	Bids *BookSide `json:"bids"`
+	Asks *BookSide `json:"asks"`
+}
+
+func (o Orderbook) MarshalJSON() ([]byte, error) {
+	return json.Marshal(orderbookJSON{Bids: o.Bids, Asks: o.Asks})
+}
+
+func (o *Orderbook) UnmarshalJSON(data []byte) error {
+	var v orderbookJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Bids = v.Bids
+	o.Asks = v.Asks
+	return nil
+}
+
+type amountsJSON struct {
+	TotalBaseTakenNative  uint64 `json:"totalBaseTakenNative"`
+	TotalQuoteTakenNative uint64 `json:"totalQuoteTakenNative"`
+	Fee                   uint64 `json:"fee"`
+	NotEnoughLiquidity    bool   `json:"notEnoughLiquidity"`
+}
+
+func (a Amounts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountsJSON(a))
+}
+
+func (a *Amounts) UnmarshalJSON(data []byte) error {
+	var v amountsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*a = Amounts(v)
+	return nil
+}
+
+type quoteJSON struct {
+	NotEnoughLiquidity bool             `json:"notEnoughLiquidity"`
+	MinInAmount        *uint64          `json:"minInAmount"`
+	MinOutAmount       *uint64          `json:"minOutAmount"`
+	InAmount           uint64           `json:"inAmount"`
+	OutAmount          uint64           `json:"outAmount"`
+	FeeAmount          uint64           `json:"feeAmount"`
+	FeeMint            solana.PublicKey `json:"feeMint"`
+	FeePct             Decimal          `json:"feePct"`
+}
+
+func (q Quote) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quoteJSON(q))
+}
+
+func (q *Quote) UnmarshalJSON(data []byte) error {
+	var v quoteJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*q = Quote(v)
+	return nil
+}
+
+const (
+	decimalSignMask  = 0x80000000
+	decimalScaleMask = 0x00FF0000
+	decimalScaleBits = 16
+	decimalMaxScale  = 28
+)
+
+func (d Decimal) scale() uint32 {
+	return (d.Flags & decimalScaleMask) >> decimalScaleBits
+}
+
+func (d Decimal) mantissa() *big.Int {
+	mantissa := big.NewInt(0).SetUint64(uint64(d.Hi))
+	mantissa.Lsh(mantissa, 32)
+	mantissa.Or(mantissa, big.NewInt(0).SetUint64(uint64(d.Mid)))
+	mantissa.Lsh(mantissa, 32)
+	return mantissa.Or(mantissa, big.NewInt(0).SetUint64(uint64(d.Lo)))
+}
+
+// String formats the decimal like rust_decimal does, e.g. "0.0004".
+func (d Decimal) String() string {
+	digits := d.mantissa().String()
+	scale := int(d.scale())
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if d.Flags&decimalSignMask != 0 {
+		digits = "-" + digits
+	}
+	return digits
+}
+
+// ParseDecimal parses a decimal string such as "-12.5" into a Decimal.
+func ParseDecimal(s string) (Decimal, error) {
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	scale := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale = len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+	if scale > decimalMaxScale {
+		return Decimal{}, errors.New("decimal scale is too large")
+	}
+
+	mantissa, ok := big.NewInt(0).SetString(s, 10)
+	if !ok || mantissa.Sign() < 0 || mantissa.BitLen() > 96 {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	mask := big.NewInt(0xFFFFFFFF)
+	d := Decimal{Flags: uint32(scale) << decimalScaleBits}
+	if negative {
+		d.Flags |= decimalSignMask
+	}
+	d.Lo = uint32(big.NewInt(0).And(mantissa, mask).Uint64())
+	d.Mid = uint32(big.NewInt(0).And(big.NewInt(0).Rsh(mantissa, 32), mask).Uint64())
+	d.Hi = uint32(big.NewInt(0).Rsh(mantissa, 64).Uint64())
+	return d, nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// optionalKey is a NonZeroPubkeyOption as a base58 string or null.
+func optionalKey(option NonZeroPubkeyOption) *solana.PublicKey {
+	if !option.IsSome() {
+		return nil
+	}
+	key := option.Key
+	return &key
+}
+
+func nonZeroPubkeyOption(key *solana.PublicKey) NonZeroPubkeyOption {
+	if key == nil {
+		return NonZeroPubkeyOption{}
+	}
+	return NonZeroPubkeyOption{Key: *key}
+}
+
+type oracleConfigJSON struct {
+	ConfFilter        float64 `json:"confFilter"`
+	MaxStalenessSlots int64   `json:"maxStalenessSlots"`
+}
+
+type marketJSON struct {
+	Bump                   uint8             `json:"bump"`
+	BaseDecimals           uint8             `json:"baseDecimals"`
+	QuoteDecimals          uint8             `json:"quoteDecimals"`
+	MarketAuthority        solana.PublicKey  `json:"marketAuthority"`
+	TimeExpiry             int64             `json:"timeExpiry"`
+	CollectFeeAdmin        solana.PublicKey  `json:"collectFeeAdmin"`
+	OpenOrdersAdmin        *solana.PublicKey `json:"openOrdersAdmin"`
+	ConsumeEventsAdmin     *solana.PublicKey `json:"consumeEventsAdmin"`
+	CloseMarketAdmin       *solana.PublicKey `json:"closeMarketAdmin"`
+	Name                   string            `json:"name"`
+	Bids                   solana.PublicKey  `json:"bids"`
+	Asks                   solana.PublicKey  `json:"asks"`
+	EventHeap              solana.PublicKey  `json:"eventHeap"`
+	OracleA                *solana.PublicKey `json:"oracleA"`
+	OracleB                *solana.PublicKey `json:"oracleB"`
+	OracleConfig           oracleConfigJSON  `json:"oracleConfig"`
+	QuoteLotSize           int64             `json:"quoteLotSize"`
+	BaseLotSize            int64             `json:"baseLotSize"`
+	SeqNum                 uint64            `json:"seqNum"`
+	RegistrationTime       int64             `json:"registrationTime"`
+	MakerFee               int64             `json:"makerFee"`
+	TakerFee               int64             `json:"takerFee"`
+	FeesAccrued            bin.Uint128       `json:"feesAccrued"`
+	FeesToReferrers        bin.Uint128       `json:"feesToReferrers"`
+	ReferrerRebatesAccrued uint64            `json:"referrerRebatesAccrued"`
+	FeesAvailable          uint64            `json:"feesAvailable"`
+	MakerVolume            bin.Uint128       `json:"makerVolume"`
+	TakerVolumeWoOo        bin.Uint128       `json:"takerVolumeWoOo"`
+	BaseMint               solana.PublicKey  `json:"baseMint"`
+	QuoteMint              solana.PublicKey  `json:"quoteMint"`
+	MarketBaseVault        solana.PublicKey  `json:"marketBaseVault"`
+	BaseDepositTotal       uint64            `json:"baseDepositTotal"`
+	MarketQuoteVault       solana.PublicKey  `json:"marketQuoteVault"`
+	QuoteDepositTotal      uint64            `json:"quoteDepositTotal"`
+}
+
+func (m Market) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marketJSON{
+		Bump:               m.Bump,
+		BaseDecimals:       m.BaseDecimals,
+		QuoteDecimals:      m.QuoteDecimals,
+		MarketAuthority:    m.MarketAuthority,
+		TimeExpiry:         m.TimeExpiry,
+		CollectFeeAdmin:    m.CollectFeeAdmin,
+		OpenOrdersAdmin:    optionalKey(m.OpenOrdersAdmin),
+		ConsumeEventsAdmin: optionalKey(m.ConsumeEventsAdmin),
+		CloseMarketAdmin:   optionalKey(m.CloseMarketAdmin),
+		Name:               m.NameString(),
+		Bids:               m.Bids,
+		Asks:               m.Asks,
+		EventHeap:          m.EventHeap,
+		OracleA:            optionalKey(m.OracleA),
+		OracleB:            optionalKey(m.OracleB),
+		OracleConfig: oracleConfigJSON{
+			ConfFilter:        m.OracleConfig.ConfFilter,
+			MaxStalenessSlots: m.OracleConfig.MaxStalenessSlots,
+		},
+		QuoteLotSize:           m.QuoteLotSize,
+		BaseLotSize:            m.BaseLotSize,
+		SeqNum:                 m.SeqNum,
+		RegistrationTime:       m.RegistrationTime,
+		MakerFee:               m.MakerFee,
+		TakerFee:               m.TakerFee,
+		FeesAccrued:            m.FeesAccrued,
+		FeesToReferrers:        m.FeesToReferrers,
+		ReferrerRebatesAccrued: m.ReferrerRebatesAccrued,
+		FeesAvailable:          m.FeesAvailable,
+		MakerVolume:            m.MakerVolume,
+		TakerVolumeWoOo:        m.TakerVolumeWoOo,
+		BaseMint:               m.BaseMint,
+		QuoteMint:              m.QuoteMint,
+		MarketBaseVault:        m.MarketBaseVault,
+		BaseDepositTotal:       m.BaseDepositTotal,
+		MarketQuoteVault:       m.MarketQuoteVault,
+		QuoteDepositTotal:      m.QuoteDepositTotal,
+	})
+}
+
+func (m *Market) UnmarshalJSON(data []byte) error {
+	var v marketJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Name) > len(m.Name) {
+		return errors.New("market name is too long")
+	}
+
+	market := Market{
+		Bump:               v.Bump,
+		BaseDecimals:       v.BaseDecimals,
+		QuoteDecimals:      v.QuoteDecimals,
+		MarketAuthority:    v.MarketAuthority,
+		TimeExpiry:         v.TimeExpiry,
+		CollectFeeAdmin:    v.CollectFeeAdmin,
+		OpenOrdersAdmin:    nonZeroPubkeyOption(v.OpenOrdersAdmin),
+		ConsumeEventsAdmin: nonZeroPubkeyOption(v.ConsumeEventsAdmin),
+		CloseMarketAdmin:   nonZeroPubkeyOption(v.CloseMarketAdmin),
+		Bids:               v.Bids,
+		Asks:               v.Asks,
+		EventHeap:          v.EventHeap,
+		OracleA:            nonZeroPubkeyOption(v.OracleA),
+		OracleB:            nonZeroPubkeyOption(v.OracleB),
+		OracleConfig: OracleConfig{
+			ConfFilter:        v.OracleConfig.ConfFilter,
+			MaxStalenessSlots: v.OracleConfig.MaxStalenessSlots,
+		},
+		QuoteLotSize:           v.QuoteLotSize,
+		BaseLotSize:            v.BaseLotSize,
+		SeqNum:                 v.SeqNum,
+		RegistrationTime:       v.RegistrationTime,
+		MakerFee:               v.MakerFee,
+		TakerFee:               v.TakerFee,
+		FeesAccrued:            v.FeesAccrued,
+		FeesToReferrers:        v.FeesToReferrers,
+		ReferrerRebatesAccrued: v.ReferrerRebatesAccrued,
+		FeesAvailable:          v.FeesAvailable,
+		MakerVolume:            v.MakerVolume,
+		TakerVolumeWoOo:        v.TakerVolumeWoOo,
+		BaseMint:               v.BaseMint,
+		QuoteMint:              v.QuoteMint,
+		MarketBaseVault:        v.MarketBaseVault,
+		BaseDepositTotal:       v.BaseDepositTotal,
+		MarketQuoteVault:       v.MarketQuoteVault,
+		QuoteDepositTotal:      v.QuoteDepositTotal,
+	}
+	copy(market.Name[:], v.Name)
+
+	*m = market
+	return nil
+}
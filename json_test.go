@@ -0,0 +1,164 @@
+package openbookdexgolang
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// newTestCodecMarket returns a market with every encoded field set, and the
+// padding and reserved bytes the encodings leave out zeroed.
+func newTestCodecMarket() *Market {
+	market := goldenMarketAccount(9, 6, 1_000_000, 1, -100, 1_000)
+	market.Bump = 254
+	market.MarketAuthority = solana.PublicKey{10}
+	market.TimeExpiry = 1_700_000_000
+	market.CollectFeeAdmin = solana.PublicKey{11}
+	market.OpenOrdersAdmin = NonZeroPubkeyOption{Key: solana.PublicKey{12}}
+	market.OracleA = NonZeroPubkeyOption{Key: solana.PublicKey{13}}
+	market.OracleConfig = OracleConfig{ConfFilter: 0.1, MaxStalenessSlots: 100}
+	market.SeqNum = 42
+	market.RegistrationTime = 1_600_000_000
+	market.FeesAccrued = bin.Uint128{Lo: 5, Hi: 1}
+	market.MakerVolume = bin.Uint128{Lo: 7}
+	market.ReferrerRebatesAccrued = 3
+	market.FeesAvailable = 4
+	market.MarketBaseVault = solana.PublicKey{14}
+	market.MarketQuoteVault = solana.PublicKey{15}
+	market.BaseDepositTotal = 8
+	market.QuoteDepositTotal = 9
+	return &market
+}
+
+// newTestCodecBook returns a built book with inner, leaf and free nodes.
+func newTestCodecBook(t *testing.T) Orderbook {
+	t.Helper()
+	book, err := BuildOrderbook([]BookOrder{
+		bid(100, 1), bid(99, 2), bid(100, 3),
+		{Side: Bid, OraclePegged: true, PriceLots: -2, PegLimit: 90, Quantity: 4, Owner: testOwner, OwnerSlot: 1, ClientOrderID: 7},
+		{Side: Ask, PriceLots: 101, Quantity: 5, Owner: testOwner, Timestamp: 10, TimeInForce: 30},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := &book.Asks.Nodes
+	free, err := newAnyNode(&FreeNode{Tag: uint8(lastFreeNode)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes.Nodes[nodes.BumpIndex] = free
+	nodes.FreeListHead = NodeHandle(nodes.BumpIndex)
+	nodes.FreeListLen = 1
+	nodes.BumpIndex++
+	return book
+}
+
+func newTestCodecQuote(t *testing.T) *Quote {
+	t.Helper()
+	feePct, err := ParseDecimal("0.0004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	minOut := uint64(90)
+	return &Quote{
+		NotEnoughLiquidity: true,
+		MinOutAmount:       &minOut,
+		InAmount:           100,
+		OutAmount:          95,
+		FeeAmount:          1,
+		FeeMint:            testQuoteMint,
+		FeePct:             feePct,
+	}
+}
+
+func jsonRoundTrip(t *testing.T, v interface{}, decoded interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("%s: %v", data, err)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	book := newTestCodecBook(t)
+	leaf := book.Bids.Nodes.LeafNode(book.Bids.Roots[OraclePeggedOrderTree].MaybeNode)
+
+	cases := []struct {
+		name    string
+		v       interface{}
+		decoded interface{}
+	}{
+		{"leaf node", leaf, &LeafNode{}},
+		{"bids", book.Bids, &BookSide{}},
+		{"asks", book.Asks, &BookSide{}},
+		{"orderbook", book, &Orderbook{}},
+		{"quote", *newTestCodecQuote(t), &Quote{}},
+		{"market", *newTestCodecMarket(), &Market{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jsonRoundTrip(t, c.v, c.decoded)
+			decoded := reflect.ValueOf(c.decoded).Elem().Interface()
+			expected := reflect.Indirect(reflect.ValueOf(c.v)).Interface()
+			if !reflect.DeepEqual(decoded, expected) {
+				t.Fatalf("decoded %+v, expected %+v", decoded, expected)
+			}
+		})
+	}
+}
+
+func TestJSONRoundTripKeepsOrders(t *testing.T) {
+	book := newTestCodecBook(t)
+	var decoded Orderbook
+	jsonRoundTrip(t, book, &decoded)
+
+	oraclePriceLots := int64(95)
+	for side, bookSide := range map[Side]*BookSide{Bid: decoded.Bids, Ask: decoded.Asks} {
+		expected := book.BookSide(side).L3(11, &oraclePriceLots)
+		if l3 := bookSide.L3(11, &oraclePriceLots); !reflect.DeepEqual(l3, expected) {
+			t.Fatalf("decoded side %d has orders %+v, expected %+v", side, l3, expected)
+		}
+	}
+}
+
+func TestDecimalLayout(t *testing.T) {
+	// Flags, hi, lo and mid as rust_decimal lays them out: the scale in bits
+	// 16 to 23 of the flags, the sign in bit 31 and a 96 bit mantissa
+	cases := []struct {
+		s        string
+		expected Decimal
+	}{
+		{"0", Decimal{}},
+		{"0.0004", Decimal{Flags: 4 << 16, Lo: 4}},
+		{"-12.5", Decimal{Flags: 0x80000000 | 1<<16, Lo: 125}},
+		{"4294967296", Decimal{Mid: 1}},
+		{"18446744073709551616", Decimal{Hi: 1}},
+		{"79228162514264337593543950335", Decimal{Hi: 0xFFFFFFFF, Mid: 0xFFFFFFFF, Lo: 0xFFFFFFFF}},
+		{"0.0000000000000000000000000001", Decimal{Flags: 28 << 16, Lo: 1}},
+	}
+	for _, c := range cases {
+		d, err := ParseDecimal(c.s)
+		if err != nil {
+			t.Fatalf("%s: %v", c.s, err)
+		}
+		if d != c.expected {
+			t.Errorf("%s parsed as %+v, expected %+v", c.s, d, c.expected)
+		}
+		if d.String() != c.s {
+			t.Errorf("%+v formatted as %s, expected %s", d, d.String(), c.s)
+		}
+	}
+
+	for _, s := range []string{"", "1.2.3", "abc", "-", "79228162514264337593543950336", "0.00000000000000000000000000001"} {
+		if _, err := ParseDecimal(s); err == nil {
+			t.Errorf("%q parsed without an error", s)
+		}
+	}
+}
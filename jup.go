@@ -1,9 +1,11 @@
 package openbookdexgolang
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/big"
 
-	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 )
 
@@ -18,11 +20,13 @@ type OpenBookMarket struct {
 	bids            BookSide
 	asks            BookSide
 	timestamp       uint64
+	timestampPinned bool
+	slot            uint64
 	key             solana.PublicKey
 	label           string
 	relatedAccounts []solana.PublicKey
 	reserveMints    [2]solana.PublicKey
-	oraclePrice     *bin.Int128
+	oraclePriceLots *int64
 	isPermissioned  bool
 }
 
@@ -30,6 +34,13 @@ type QuoteParams struct {
 	InAmount   uint64
 	InputMint  solana.PublicKey
 	OutputMint solana.PublicKey
+	SelfTrade  *SelfTradeConfig // Optional, the taker's account and self trade behavior
+
+	// Optional bounds on the matched prices. With several of them the
+	// tightest applies.
+	LimitPriceLots *int64   // Worst price in lots
+	LimitPriceUi   *float64 // Worst ui price
+	SlippageBps    *uint64  // Worst price as bps away from the best opposing order
 }
 
 type Quote struct {
@@ -43,22 +54,233 @@ type Quote struct {
 	FeePct             Decimal
 }
 
+// NewOpenBookMarket creates the market from its decoded Market account. The
+// book, event heap and timestamp are empty until the first Update.
+func NewOpenBookMarket(key solana.PublicKey, market *Market) *OpenBookMarket {
+	isPermissioned := market.OpenOrdersAdmin.IsSome()
+
+	relatedAccounts := make([]solana.PublicKey, 0)
+	if !isPermissioned {
+		relatedAccounts = append(relatedAccounts,
+			market.Bids,
+			market.Asks,
+			market.EventHeap,
+			market.MarketBaseVault,
+			market.MarketQuoteVault,
+			solana.SysVarClockPubkey,
+		)
+	}
+
+	return &OpenBookMarket{
+		market:          *market,
+		key:             key,
+		label:           market.NameString(),
+		relatedAccounts: relatedAccounts,
+		reserveMints:    [2]solana.PublicKey{market.BaseMint, market.QuoteMint},
+		isPermissioned:  isPermissioned,
+	}
+}
+
+func (obm *OpenBookMarket) Key() solana.PublicKey {
+	return obm.key
+}
+
+func (obm *OpenBookMarket) Label() string {
+	return obm.label
+}
+
+func (obm *OpenBookMarket) Market() *Market {
+	return &obm.market
+}
+
+func (obm *OpenBookMarket) ReserveMints() [2]solana.PublicKey {
+	return obm.reserveMints
+}
+
+// Orderbook returns the market's book. It points into the market and is only
+// valid until the next Update.
+func (obm *OpenBookMarket) Orderbook() Orderbook {
+	return Orderbook{Bids: &obm.bids, Asks: &obm.asks}
+}
+
+// Timestamp returns the unix timestamp orders are expired at when quoting,
+// taken from the Clock sysvar on Update unless it is overridden.
+func (obm *OpenBookMarket) Timestamp() uint64 {
+	return obm.timestamp
+}
+
+// OverrideTimestamp pins the timestamp, for example to replay historical
+// books. Updates then no longer need nor read the Clock sysvar. A nil
+// timestamp unpins it, and the next Update reads the clock again.
+func (obm *OpenBookMarket) OverrideTimestamp(timestamp *uint64) {
+	if timestamp == nil {
+		obm.timestampPinned = false
+		return
+	}
+	obm.timestamp = *timestamp
+	obm.timestampPinned = true
+}
+
+func (obm *OpenBookMarket) GetAccountsToUpdate() []solana.PublicKey {
+	return obm.relatedAccounts
+}
+
+// Update decodes the bids, asks, event heap and Clock sysvar from the given
+// account data, keyed by address.
+func (obm *OpenBookMarket) Update(accounts map[solana.PublicKey][]byte) error {
+	if obm.isPermissioned {
+		return nil
+	}
+
+	bidsData, ok := accounts[obm.market.Bids]
+	if !ok {
+		return errors.New("bids account not found")
+	}
+	bids, err := DecodeBookSide(bidsData)
+	if err != nil {
+		return err
+	}
+
+	asksData, ok := accounts[obm.market.Asks]
+	if !ok {
+		return errors.New("asks account not found")
+	}
+	asks, err := DecodeBookSide(asksData)
+	if err != nil {
+		return err
+	}
+
+	eventHeapData, ok := accounts[obm.market.EventHeap]
+	if !ok {
+		return errors.New("event heap account not found")
+	}
+	eventHeap, err := DecodeEventHeap(eventHeapData)
+	if err != nil {
+		return err
+	}
+
+	timestamp := obm.timestamp
+	if !obm.timestampPinned {
+		clockData, ok := accounts[solana.SysVarClockPubkey]
+		if !ok {
+			return errors.New("clock account not found")
+		}
+		clock, err := DecodeClock(clockData)
+		if err != nil {
+			return err
+		}
+		timestamp = uint64(clock.UnixTimestamp)
+	}
+
+	obm.bids = *bids
+	obm.asks = *asks
+	obm.eventHeap = *eventHeap
+	obm.timestamp = timestamp
+	return nil
+}
+
+// takerSide returns the side of a taker order paying with inputMint.
+func (obm *OpenBookMarket) takerSide(inputMint solana.PublicKey) Side {
+	if inputMint == obm.market.QuoteMint {
+		return SideBid
+	}
+	return SideAsk
+}
+
+// OraclePriceLots returns the oracle price pegged orders are priced at, in
+// lots, or nil when it isn't known and pegged orders are ignored.
+func (obm *OpenBookMarket) OraclePriceLots() *int64 {
+	return obm.oraclePriceLots
+}
+
+// SetOraclePriceLots sets the oracle price in lots pegged orders are priced
+// at, nil to ignore them. Update doesn't read the OracleA and OracleB
+// accounts, so the price is kept until it is set again, here or by
+// UpdateOraclePrice.
+func (obm *OpenBookMarket) SetOraclePriceLots(priceLots *int64) {
+	if priceLots == nil {
+		obm.oraclePriceLots = nil
+		return
+	}
+	price := *priceLots
+	obm.oraclePriceLots = &price
+}
+
+// OracleAccounts returns the OracleA and OracleB accounts of the market, read
+// by UpdateOraclePrice together with the Clock sysvar.
+func (obm *OpenBookMarket) OracleAccounts() []solana.PublicKey {
+	keys := make([]solana.PublicKey, 0, 2)
+	for _, oracle := range []NonZeroPubkeyOption{obm.market.OracleA, obm.market.OracleB} {
+		if oracle.IsSome() {
+			keys = append(keys, oracle.Key)
+		}
+	}
+	return keys
+}
+
+// UpdateOraclePrice sets the oracle price from the OracleA and OracleB
+// accounts, checked against the OracleConfig of the market at the slot of the
+// Clock sysvar. Like the program, pegged orders are ignored when the price
+// can't be used: it is then cleared and the reason returned.
+func (obm *OpenBookMarket) UpdateOraclePrice(accounts map[solana.PublicKey][]byte) error {
+	obm.oraclePriceLots = nil
+	if !obm.market.OracleA.IsSome() {
+		return nil
+	}
+
+	clockData, ok := accounts[solana.SysVarClockPubkey]
+	if !ok {
+		return errors.New("clock account not found")
+	}
+	clock, err := DecodeClock(clockData)
+	if err != nil {
+		return err
+	}
+
+	oracles := make([]*OraclePrice, 0, 2)
+	for _, key := range obm.OracleAccounts() {
+		data, ok := accounts[key]
+		if !ok {
+			return fmt.Errorf("oracle account %s not found", key)
+		}
+		oracle, err := DecodeOracle(data)
+		if err != nil {
+			return fmt.Errorf("oracle %s: %w", key, err)
+		}
+		oracles = append(oracles, oracle)
+	}
+	oracles = append(oracles, nil)
+
+	priceLots, err := obm.market.OraclePriceLots(oracles[0], oracles[1], clock.Slot)
+	if err != nil {
+		return err
+	}
+	obm.oraclePriceLots = &priceLots
+	return nil
+}
+
 func (obm *OpenBookMarket) Quote(quoteParams *QuoteParams) (*Quote, error) {
+	quote, _, err := obm.quote(quoteParams, false)
+	return quote, err
+}
+
+// QuoteWithTrace quotes like Quote and also returns the traced take behind
+// the quote, with every order it visited and why it stopped.
+func (obm *OpenBookMarket) QuoteWithTrace(quoteParams *QuoteParams) (*Quote, *TakeSimulation, error) {
+	return obm.quote(quoteParams, true)
+}
+
+func (obm *OpenBookMarket) quote(quoteParams *QuoteParams, trace bool) (*Quote, *TakeSimulation, error) {
 	// Check if the market is permissioned
 	if obm.isPermissioned {
 		return &Quote{
 			NotEnoughLiquidity: true,
 			// Default other fields if needed (you may need to define default behavior for Quote)
-		}, nil
+		}, &TakeSimulation{}, nil
 	}
 
 	// Determine the side based on input mint
-	var side Side
-	if quoteParams.InputMint == obm.market.QuoteMint {
-		side = SideBid
-	} else {
-		side = SideAsk
-	}
+	side := obm.takerSide(quoteParams.InputMint)
 
 	// Convert input amount to int64
 	inputAmount := int64(quoteParams.InAmount)
@@ -68,32 +290,35 @@ func (obm *OpenBookMarket) Quote(quoteParams *QuoteParams) (*Quote, error) {
 	switch side {
 	case SideBid:
 		maxBaseLots = obm.market.MaxBaseLots()
-		maxQuoteLotsIncludingFees = (inputAmount + int64(obm.market.QuoteLotSize) - 1) / int64(obm.market.QuoteLotSize)
+		maxQuoteLotsIncludingFees = divCeil(inputAmount, obm.market.QuoteLotSize)
 	case SideAsk:
-		maxBaseLots = (inputAmount + int64(obm.market.BaseLotSize) - 1) / int64(obm.market.BaseLotSize)
+		maxBaseLots = divCeil(inputAmount, obm.market.BaseLotSize)
 		maxQuoteLotsIncludingFees = obm.market.MaxQuoteLots()
 	}
 
-	// Use Go references to create mutable order book references
-	bidsRef := obm.bids
-	asksRef := obm.asks
-	book := Orderbook{
-		Bids: &bidsRef,
-		Asks: &asksRef,
+	// Matching is simulated without modifying the book, so it can be read in
+	// place instead of being copied on every quote
+	book := obm.Orderbook()
+
+	limitPriceLots, err := obm.limitPriceLots(side, quoteParams)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Calculate order amounts from the order book
-	orderAmounts, err := AmountsFromBook(
+	orderAmounts, take, err := amountsFromBook(
 		book,
 		side,
 		maxBaseLots,
 		maxQuoteLotsIncludingFees,
 		&obm.market,
-		big.NewFloat(0).SetInt(obm.oraclePrice.BigInt()),
-		0,
+		// No oracle price means oracle pegged orders are ignored
+		obm.oraclePriceLots,
+		obm.timestamp,
+		TakeOptions{SelfTrade: quoteParams.SelfTrade, LimitPriceLots: limitPriceLots, Trace: trace},
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Calculate in_amount and out_amount based on the side
@@ -111,9 +336,96 @@ func (obm *OpenBookMarket) Quote(quoteParams *QuoteParams) (*Quote, error) {
 	return &Quote{
 		InAmount:           uint64(inAmount),
 		OutAmount:          uint64(outAmount),
+		MinOutAmount:       obm.minOutAmount(side, quoteParams.InAmount, limitPriceLots),
 		FeeMint:            obm.market.QuoteMint,
 		FeeAmount:          orderAmounts.Fee,
 		NotEnoughLiquidity: orderAmounts.NotEnoughLiquidity,
 		// You can initialize other fields of Quote here as needed
-	}, nil
+	}, &take, nil
+}
+
+// limitPriceLots returns the tightest of the price bounds of the quote, or nil
+// when it has none.
+func (obm *OpenBookMarket) limitPriceLots(side Side, quoteParams *QuoteParams) (*int64, error) {
+	var limit *int64
+	tighten := func(priceLots int64) {
+		// The price worse for the taker is the tighter bound
+		if limit == nil || side.IsPriceBetter(*limit, priceLots) {
+			limit = &priceLots
+		}
+	}
+
+	if quoteParams.LimitPriceLots != nil {
+		tighten(*quoteParams.LimitPriceLots)
+	}
+
+	if quoteParams.LimitPriceUi != nil {
+		// Round towards the better price so the ui limit is never exceeded
+		priceLots := obm.market.UiPriceToLots(*quoteParams.LimitPriceUi)
+		if side == SideBid {
+			tighten(int64(math.Floor(priceLots)))
+		} else {
+			tighten(int64(math.Ceil(priceLots)))
+		}
+	}
+
+	if quoteParams.SlippageBps != nil {
+		// Without any valid opposing order nothing matches anyway
+		book := obm.Orderbook()
+		iter := book.BookSide(side.InvertSide()).IterAllIncludingInvalid(obm.timestamp, obm.oraclePriceLots)
+		for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+			if !item.IsValid() {
+				continue
+			}
+			best := big.NewInt(item.PriceLots)
+			if side == SideBid {
+				best.Mul(best, big.NewInt(int64(10_000+*quoteParams.SlippageBps)))
+				best.Div(best, big.NewInt(10_000))
+			} else {
+				bps := int64(10_000) - int64(*quoteParams.SlippageBps)
+				if bps < 0 {
+					bps = 0
+				}
+				best.Mul(best, big.NewInt(bps))
+				best.Add(best, big.NewInt(10_000-1))
+				best.Div(best, big.NewInt(10_000))
+			}
+			if best.IsInt64() {
+				tighten(best.Int64())
+			}
+			break
+		}
+	}
+
+	return limit, nil
+}
+
+// minOutAmount returns the least a take of inAmount bounded by the limit price
+// gives back, or nil without a limit. It assumes the whole amount is matched,
+// at the limit price. The amount is converted to lots and fees are treated
+// like Quote does: taker fees are only set aside from the quote of bids.
+func (obm *OpenBookMarket) minOutAmount(side Side, inAmount uint64, limitPriceLots *int64) *uint64 {
+	if limitPriceLots == nil || *limitPriceLots < 1 {
+		return nil
+	}
+
+	// Lot sizes and the limit are only bounded by int64, so are their products
+	var minOut *big.Int
+	switch side {
+	case SideBid:
+		quoteLots := obm.market.SubtractTakerFees(divCeil(int64(inAmount), obm.market.QuoteLotSize))
+		minOut = big.NewInt(quoteLots / *limitPriceLots)
+		minOut.Mul(minOut, big.NewInt(obm.market.BaseLotSize))
+	case SideAsk:
+		minOut = big.NewInt(divCeil(int64(inAmount), obm.market.BaseLotSize))
+		minOut.Mul(minOut, big.NewInt(*limitPriceLots))
+		minOut.Mul(minOut, big.NewInt(obm.market.QuoteLotSize))
+	default:
+		return nil
+	}
+	if !minOut.IsUint64() {
+		return nil
+	}
+	amount := minOut.Uint64()
+	return &amount
 }
@@ -0,0 +1,192 @@
+package openbookdexgolang
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestLimitPriceLots(t *testing.T) {
+	orders := []BookOrder{
+		ask(100, 10), ask(102, 10), ask(105, 10),
+		bid(98, 10), bid(96, 10), bid(90, 10),
+	}
+	obm := newTestMarket(t, newTestMarketAccount(), orders...)
+
+	tests := []struct {
+		name      string
+		inputMint bool // Pays with the quote mint, buying base
+		params    QuoteParams
+		expected  *int64
+	}{
+		{name: "no bound", inputMint: true},
+		{name: "bid limit", inputMint: true, params: QuoteParams{LimitPriceLots: int64Ptr(105)}, expected: int64Ptr(105)},
+		{name: "bid ui limit below lots limit", inputMint: true, params: QuoteParams{LimitPriceLots: int64Ptr(105), LimitPriceUi: float64Ptr(100.5)}, expected: int64Ptr(100)},
+		{name: "bid lots limit below ui limit", inputMint: true, params: QuoteParams{LimitPriceLots: int64Ptr(100), LimitPriceUi: float64Ptr(105)}, expected: int64Ptr(100)},
+		// 2% above the best ask of 100
+		{name: "bid slippage", inputMint: true, params: QuoteParams{SlippageBps: uint64Ptr(200)}, expected: int64Ptr(102)},
+		{name: "bid slippage below limit", inputMint: true, params: QuoteParams{LimitPriceLots: int64Ptr(110), SlippageBps: uint64Ptr(200)}, expected: int64Ptr(102)},
+		{name: "bid limit below slippage", inputMint: true, params: QuoteParams{LimitPriceLots: int64Ptr(101), SlippageBps: uint64Ptr(1_000)}, expected: int64Ptr(101)},
+		{name: "ask limit", params: QuoteParams{LimitPriceLots: int64Ptr(95)}, expected: int64Ptr(95)},
+		{name: "ask ui limit above lots limit", params: QuoteParams{LimitPriceLots: int64Ptr(95), LimitPriceUi: float64Ptr(96.5)}, expected: int64Ptr(97)},
+		// 3% below the best bid of 98, rounded up
+		{name: "ask slippage", params: QuoteParams{SlippageBps: uint64Ptr(300)}, expected: int64Ptr(96)},
+		{name: "ask slippage above limit", params: QuoteParams{LimitPriceLots: int64Ptr(90), SlippageBps: uint64Ptr(300)}, expected: int64Ptr(96)},
+		{name: "ask limit above slippage", params: QuoteParams{LimitPriceLots: int64Ptr(97), SlippageBps: uint64Ptr(1_000)}, expected: int64Ptr(97)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := test.params
+			params.InputMint, params.OutputMint = testBaseMint, testQuoteMint
+			if test.inputMint {
+				params.InputMint, params.OutputMint = testQuoteMint, testBaseMint
+			}
+
+			limit, err := obm.limitPriceLots(obm.takerSide(params.InputMint), &params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case test.expected == nil && limit != nil:
+				t.Fatalf("limit %d, expected none", *limit)
+			case test.expected != nil && limit == nil:
+				t.Fatalf("no limit, expected %d", *test.expected)
+			case test.expected != nil && *limit != *test.expected:
+				t.Fatalf("limit %d, expected %d", *limit, *test.expected)
+			}
+		})
+	}
+}
+
+func TestQuoteStopsAtTightestLimit(t *testing.T) {
+	obm := newTestMarket(t, newTestMarketAccount(), ask(100, 10), ask(102, 10), ask(105, 10))
+
+	// Enough quote for every ask, only the ones at or below 102 may match
+	quote, err := obm.Quote(&QuoteParams{
+		InAmount:       10_000,
+		InputMint:      testQuoteMint,
+		OutputMint:     testBaseMint,
+		LimitPriceLots: int64Ptr(110),
+		SlippageBps:    uint64Ptr(200),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.OutAmount != 20 || quote.InAmount != 10*100+10*102 {
+		t.Fatalf("quote in %d out %d, expected in %d out 20", quote.InAmount, quote.OutAmount, 10*100+10*102)
+	}
+	if quote.MinOutAmount == nil || *quote.MinOutAmount != 10_000/102 {
+		t.Fatalf("min out %v, expected %d", quote.MinOutAmount, 10_000/102)
+	}
+}
+
+func TestMinOutAmountRoundsLikeQuote(t *testing.T) {
+	market := newTestMarketAccount()
+	market.QuoteLotSize = 10
+	market.BaseLotSize = 10
+	obm := newTestMarket(t, market, ask(10, 100))
+
+	// 105 native quote is 11 lots, like the quote's maximum
+	minOut := obm.minOutAmount(SideBid, 105, int64Ptr(1))
+	if minOut == nil || *minOut != 110 {
+		t.Fatalf("min out %v, expected 110", minOut)
+	}
+
+	minOut = obm.minOutAmount(SideAsk, 15, int64Ptr(1))
+	if minOut == nil || *minOut != 20 {
+		t.Fatalf("min out %v, expected 20", minOut)
+	}
+}
+
+func TestMinOutAmountOfFillAtLimit(t *testing.T) {
+	market := newTestMarketAccount()
+	market.TakerFee = 1_000
+
+	// A take matched entirely at its limit price gives back exactly the
+	// minimum, on both sides
+	for _, test := range []struct {
+		name     string
+		order    BookOrder
+		inAmount uint64
+		input    solana.PublicKey
+		output   solana.PublicKey
+	}{
+		{"bid", ask(100, 10), 1_000, testQuoteMint, testBaseMint},
+		{"ask", bid(100, 10), 10, testBaseMint, testQuoteMint},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			obm := newTestMarket(t, market, test.order)
+			quote, err := obm.Quote(&QuoteParams{
+				InAmount:       test.inAmount,
+				InputMint:      test.input,
+				OutputMint:     test.output,
+				LimitPriceLots: int64Ptr(100),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.MinOutAmount == nil || *quote.MinOutAmount != quote.OutAmount {
+				t.Fatalf("min out %v, expected the out amount %d", quote.MinOutAmount, quote.OutAmount)
+			}
+		})
+	}
+}
+
+func TestMinOutAmountOverflow(t *testing.T) {
+	market := newTestMarketAccount()
+	market.QuoteLotSize = 1 << 40
+	obm := newTestMarket(t, market)
+
+	// The limit times the quote lot size is past int64 but the amount fits
+	minOut := obm.minOutAmount(SideAsk, 1, int64Ptr(1<<23))
+	if minOut == nil || *minOut != 1<<63 {
+		t.Fatalf("min out %v, expected %d", minOut, uint64(1<<63))
+	}
+
+	minOut = obm.minOutAmount(SideAsk, 1, int64Ptr(1<<24))
+	if minOut != nil {
+		t.Fatalf("min out %d, expected none past uint64", *minOut)
+	}
+}
+
+func TestQuoteUsesOraclePrice(t *testing.T) {
+	pegged := BookOrder{Side: Ask, OraclePegged: true, PriceLots: -2, PegLimit: -1, Quantity: 10, Owner: testOwner}
+	obm := newTestMarket(t, newTestMarketAccount(), ask(100, 10), pegged)
+	params := &QuoteParams{InAmount: 980, InputMint: testQuoteMint, OutputMint: testBaseMint}
+
+	// Without an oracle price the pegged ask is ignored
+	quote, err := obm.Quote(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.OutAmount != 9 {
+		t.Fatalf("out %d, expected 9 at 100", quote.OutAmount)
+	}
+
+	// At an oracle price of 100 it is the best ask, at 98
+	oraclePriceLots := int64(100)
+	obm.SetOraclePriceLots(&oraclePriceLots)
+	oraclePriceLots = 0
+	if price := obm.OraclePriceLots(); price == nil || *price != 100 {
+		t.Fatalf("oracle price %v, expected 100", price)
+	}
+	quote, err = obm.Quote(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.OutAmount != 10 || quote.InAmount != 980 {
+		t.Fatalf("in %d out %d, expected in 980 out 10", quote.InAmount, quote.OutAmount)
+	}
+
+	obm.SetOraclePriceLots(nil)
+	if obm.OraclePriceLots() != nil {
+		t.Fatal("oracle price kept after clearing it")
+	}
+}
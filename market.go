@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"strings"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -112,6 +113,26 @@ type Market struct {
 	Reserved [128]byte
 }
 
+// NameString returns the market name without its trailing zero bytes.
+func (m *Market) NameString() string {
+	return strings.TrimRight(string(m.Name[:]), "\x00")
+}
+
+// validate checks the bounds create_market puts on lot sizes and fees, which
+// the conversions and fee computations rely on.
+func (m *Market) validate() error {
+	if m.BaseLotSize <= 0 || m.QuoteLotSize <= 0 {
+		return errors.New("lot sizes must be greater than 0")
+	}
+	if m.TakerFee < 0 || m.TakerFee >= FEES_SCALE_FACTOR {
+		return errors.New("taker fee out of range")
+	}
+	if m.MakerFee <= -FEES_SCALE_FACTOR || m.MakerFee >= FEES_SCALE_FACTOR {
+		return errors.New("maker fee out of range")
+	}
+	return nil
+}
+
 func (m *Market) MaxBaseLots() int64 {
 	return math.MaxInt64 / m.BaseLotSize
 }
@@ -120,19 +141,30 @@ func (m *Market) MaxQuoteLots() int64 {
 	return math.MaxInt64 / m.QuoteLotSize
 }
 
+// NativePriceToLot converts a native price to lots, rounding down like the
+// fixed point conversion of the program. It fails when the result doesn't fit
+// in an i64.
 func (m *Market) NativePriceToLot(price *big.Float) (int64, error) {
 	baseLotSize := big.NewFloat(float64(m.BaseLotSize))
 	quoteLotSize := big.NewFloat(float64(m.QuoteLotSize))
 
-	result := price.Mul(price, baseLotSize)
+	// Work on a copy, the caller's price is left untouched
+	result := big.NewFloat(0).SetPrec(256).Mul(price, baseLotSize)
 	result.Quo(result, quoteLotSize)
 
-	if !result.IsInt() {
+	if result.IsInf() {
 		return 0, errors.New("invalid oracle price")
 	}
 
-	intResult, _ := result.Int64()
-	return intResult, nil
+	// Round towards negative infinity
+	intResult, _ := result.Int(nil)
+	if result.Sign() < 0 && !result.IsInt() {
+		intResult.Sub(intResult, big.NewInt(1))
+	}
+	if !intResult.IsInt64() {
+		return 0, errors.New("invalid oracle price")
+	}
+	return intResult.Int64(), nil
 }
 
 func (m *Market) subtractTakerFees(quote int64) int64 {
@@ -167,6 +199,55 @@ func (m *Market) unsignedMakerFeesFloor(amount uint64) uint64 {
 	return result.Uint64()
 }
 
+// SubtractTakerFees returns the quote left for matching once taker fees are
+// set aside. It is computed on integers, floats lose precision for quotes as
+// large as MaxQuoteLots.
 func (m *Market) SubtractTakerFees(quote int64) int64 {
-	return int64((float64(quote) * float64(FEES_SCALE_FACTOR)) / (float64(FEES_SCALE_FACTOR) + float64(m.TakerFee)))
+	return m.subtractTakerFees(quote)
+}
+
+func (m *Market) TakerFeesCeil(amount uint64) uint64 {
+	return ceilFees(amount, m.TakerFee)
+}
+
+func (m *Market) MakerFeesCeil(amount uint64) uint64 {
+	if m.MakerFee < 0 {
+		return 0
+	}
+	return ceilFees(amount, m.MakerFee)
+}
+
+func ceilFees(amount uint64, fee int64) uint64 {
+	amountBig := big.NewInt(0).SetUint64(amount)
+	feeBig := big.NewInt(fee)
+	feesScaleFactorBig := big.NewInt(FEES_SCALE_FACTOR)
+
+	result := amountBig.Mul(amountBig, feeBig)
+	result.Add(result, feesScaleFactorBig)
+	result.Sub(result, big.NewInt(1))
+	result.Div(result, feesScaleFactorBig)
+
+	return result.Uint64()
+}
+
+// PriceLotsToUi converts a price in lots to a ui price.
+func (m *Market) PriceLotsToUi(priceLots int64) float64 {
+	nativePrice := float64(priceLots) * float64(m.QuoteLotSize) / float64(m.BaseLotSize)
+	return nativePrice * math.Pow10(int(m.BaseDecimals)-int(m.QuoteDecimals))
+}
+
+// UiPriceToLots converts a ui price to a price in lots, without rounding.
+func (m *Market) UiPriceToLots(uiPrice float64) float64 {
+	nativePrice := uiPrice * math.Pow10(int(m.QuoteDecimals)-int(m.BaseDecimals))
+	return nativePrice * float64(m.BaseLotSize) / float64(m.QuoteLotSize)
+}
+
+// BaseLotsToUi converts base lots to a ui base amount.
+func (m *Market) BaseLotsToUi(baseLots int64) float64 {
+	return float64(baseLots*m.BaseLotSize) / math.Pow10(int(m.BaseDecimals))
+}
+
+// QuoteNativeToUi converts native quote to a ui quote amount.
+func (m *Market) QuoteNativeToUi(quoteNative int64) float64 {
+	return float64(quoteNative) / math.Pow10(int(m.QuoteDecimals))
 }
@@ -0,0 +1,79 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Offsets in the Market account data, discriminator included
+var (
+	marketAccountSize     = len(marketDiscriminator) + encodedSize(Market{})
+	marketBaseMintOffset  = marketFieldOffset("BaseMint")
+	marketQuoteMintOffset = marketFieldOffset("QuoteMint")
+)
+
+// DiscoveredMarket is a Market account found through the RPC.
+type DiscoveredMarket struct {
+	Address solana.PublicKey
+	Market  *Market
+}
+
+// FindMarkets lists the OpenBook v2 markets trading baseMint against
+// quoteMint. A nil mint matches any, so FindMarkets(ctx, client, nil, nil)
+// lists every market of the program.
+func FindMarkets(ctx context.Context, client RPCClient, baseMint, quoteMint *solana.PublicKey) ([]DiscoveredMarket, error) {
+	filters := []RPCFilter{
+		{DataSize: uint64(marketAccountSize)},
+		{Memcmp: &RPCFilterMemcmp{Offset: 0, Bytes: marketDiscriminator[:]}},
+	}
+	if baseMint != nil {
+		filters = append(filters, RPCFilter{Memcmp: &RPCFilterMemcmp{Offset: uint64(marketBaseMintOffset), Bytes: baseMint.Bytes()}})
+	}
+	if quoteMint != nil {
+		filters = append(filters, RPCFilter{Memcmp: &RPCFilterMemcmp{Offset: uint64(marketQuoteMintOffset), Bytes: quoteMint.Bytes()}})
+	}
+
+	accounts, err := client.GetProgramAccounts(ctx, OPENBOOK_V2_PROGRAM_ID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	markets := make([]DiscoveredMarket, 0, len(accounts))
+	for _, account := range accounts {
+		market, err := DecodeMarket(account.Data)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, DiscoveredMarket{Address: account.Address, Market: market})
+	}
+	return markets, nil
+}
+
+// marketFieldOffset returns the offset of a Market field in the account data,
+// adding up the encoded sizes of the fields before it. They all have a fixed
+// size.
+func marketFieldOffset(name string) int {
+	marketType := reflect.TypeOf(Market{})
+	offset := len(marketDiscriminator)
+	for i := 0; i < marketType.NumField(); i++ {
+		field := marketType.Field(i)
+		if field.Name == name {
+			return offset
+		}
+		offset += encodedSize(reflect.Zero(field.Type).Interface())
+	}
+	panic("no Market field " + name)
+}
+
+// encodedSize returns the borsh encoded size of v.
+func encodedSize(v interface{}) int {
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBorshEncoder(buf).Encode(v); err != nil {
+		panic(err)
+	}
+	return buf.Len()
+}
@@ -0,0 +1,118 @@
+package openbookdexgolang_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+	"github.com/texora/openbook-dex-golang/rpctest"
+)
+
+var (
+	mintA = solana.PublicKey{0xa}
+	mintB = solana.PublicKey{0xb}
+	mintC = solana.PublicKey{0xc}
+	mintD = solana.PublicKey{0xd}
+)
+
+func encodeMarket(t *testing.T, baseMint, quoteMint solana.PublicKey) []byte {
+	t.Helper()
+	market := openbook.Market{BaseMint: baseMint, QuoteMint: quoteMint, BaseLotSize: 1, QuoteLotSize: 1}
+	buf := bytes.NewBuffer(nil)
+	buf.Write(openbook.MarketDiscriminator[:])
+	if err := bin.NewBorshEncoder(buf).Encode(&market); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// filterRecorder keeps the filters of the getProgramAccounts calls it forwards.
+type filterRecorder struct {
+	openbook.RPCClient
+	filters []openbook.RPCFilter
+}
+
+func (r *filterRecorder) GetProgramAccounts(ctx context.Context, program solana.PublicKey, filters []openbook.RPCFilter) ([]openbook.ProgramAccount, error) {
+	r.filters = filters
+	return r.RPCClient.GetProgramAccounts(ctx, program, filters)
+}
+
+func TestFindMarkets(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+
+	program := openbook.OPENBOOK_V2_PROGRAM_ID
+	marketAB, marketBA, marketAC := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}
+	server.SetAccount(marketAB, rpctest.Account{Owner: program, Data: encodeMarket(t, mintA, mintB)})
+	server.SetAccount(marketBA, rpctest.Account{Owner: program, Data: encodeMarket(t, mintB, mintA)})
+	server.SetAccount(marketAC, rpctest.Account{Owner: program, Data: encodeMarket(t, mintA, mintC)})
+
+	// Accounts only the filters keep out: another account type of the same
+	// size, a market with trailing data and a market of another program
+	otherType := encodeMarket(t, mintA, mintB)
+	copy(otherType, []byte("notmarkt"))
+	server.SetAccount(solana.PublicKey{4}, rpctest.Account{Owner: program, Data: otherType})
+	server.SetAccount(solana.PublicKey{5}, rpctest.Account{Owner: program, Data: append(encodeMarket(t, mintA, mintB), 0)})
+	server.SetAccount(solana.PublicKey{6}, rpctest.Account{Owner: solana.SystemProgramID, Data: encodeMarket(t, mintA, mintB)})
+
+	client := &filterRecorder{RPCClient: openbook.NewHTTPRPCClient(server.URL())}
+	tests := []struct {
+		name      string
+		baseMint  *solana.PublicKey
+		quoteMint *solana.PublicKey
+		expected  []solana.PublicKey
+	}{
+		{name: "base and quote", baseMint: &mintA, quoteMint: &mintB, expected: []solana.PublicKey{marketAB}},
+		{name: "reversed pair", baseMint: &mintB, quoteMint: &mintA, expected: []solana.PublicKey{marketBA}},
+		{name: "no match", baseMint: &mintA, quoteMint: &mintD, expected: []solana.PublicKey{}},
+		{name: "base only", baseMint: &mintA, expected: []solana.PublicKey{marketAB, marketAC}},
+		{name: "quote only", quoteMint: &mintA, expected: []solana.PublicKey{marketBA}},
+		{name: "every market", expected: []solana.PublicKey{marketAB, marketBA, marketAC}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			markets, err := openbook.FindMarkets(context.Background(), client, test.baseMint, test.quoteMint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			addresses := make([]solana.PublicKey, 0, len(markets))
+			for _, market := range markets {
+				addresses = append(addresses, market.Address)
+				if test.baseMint != nil && market.Market.BaseMint != *test.baseMint {
+					t.Fatalf("market %s has base mint %s", market.Address, market.Market.BaseMint)
+				}
+				if test.quoteMint != nil && market.Market.QuoteMint != *test.quoteMint {
+					t.Fatalf("market %s has quote mint %s", market.Address, market.Market.QuoteMint)
+				}
+			}
+			if !reflect.DeepEqual(addresses, test.expected) {
+				t.Fatalf("found %v, expected %v", addresses, test.expected)
+			}
+		})
+	}
+}
+
+func TestFindMarketsFilters(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+
+	client := &filterRecorder{RPCClient: openbook.NewHTTPRPCClient(server.URL())}
+	if _, err := openbook.FindMarkets(context.Background(), client, &mintA, &mintB); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []openbook.RPCFilter{
+		{DataSize: 848},
+		{Memcmp: &openbook.RPCFilterMemcmp{Offset: 0, Bytes: openbook.MarketDiscriminator[:]}},
+		{Memcmp: &openbook.RPCFilterMemcmp{Offset: 576, Bytes: mintA.Bytes()}},
+		{Memcmp: &openbook.RPCFilterMemcmp{Offset: 608, Bytes: mintB.Bytes()}},
+	}
+	if !reflect.DeepEqual(client.filters, expected) {
+		t.Fatalf("filters %+v, expected %+v", client.filters, expected)
+	}
+}
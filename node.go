@@ -1,9 +1,10 @@
 package openbookdexgolang
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"math"
-	"unsafe"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -33,27 +34,133 @@ type LeafNode struct {
 	ClientOrderID uint64
 }
 
+type FreeNode struct {
+	Tag        uint8
+	Padding    [3]byte
+	Next       NodeHandle
+	Reserved   [72]byte
+	ForceAlign uint64
+}
+
+// bytes returns the node as laid out in the account data.
+func (node *AnyNode) bytes() []byte {
+	data := make([]byte, 0, 1+len(node.Data)+8)
+	data = append(data, node.Tag)
+	data = append(data, node.Data[:]...)
+	return binary.LittleEndian.AppendUint64(data, node.ForceAlign)
+}
+
+// decode reads the node as an InnerNode, LeafNode or FreeNode, depending on v.
+func (node *AnyNode) decode(v interface{}) error {
+	return bin.NewBorshDecoder(node.bytes()).Decode(v)
+}
+
+// newAnyNode lays out an InnerNode, LeafNode or FreeNode as an AnyNode.
+func newAnyNode(v interface{}) (AnyNode, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBorshEncoder(buf).Encode(v); err != nil {
+		return AnyNode{}, err
+	}
+
+	var node AnyNode
+	data := buf.Bytes()
+	if len(data) != 1+len(node.Data)+8 {
+		return AnyNode{}, errors.New("invalid node size")
+	}
+	node.Tag = data[0]
+	copy(node.Data[:], data[1:])
+	node.ForceAlign = binary.LittleEndian.Uint64(data[1+len(node.Data):])
+	return node, nil
+}
+
+// The node decoders below read the fields at their on-chain offsets without
+// allocating. Offsets are into Data, which starts after the tag byte.
+
+func (node *AnyNode) leafNode() LeafNode {
+	d := &node.Data
+	leaf := LeafNode{
+		Tag:         node.Tag,
+		OwnerSlot:   d[0],
+		TimeInForce: binary.LittleEndian.Uint16(d[1:3]),
+		Key: bin.Uint128{
+			Lo: binary.LittleEndian.Uint64(d[7:15]),
+			Hi: binary.LittleEndian.Uint64(d[15:23]),
+		},
+		Quantity:      int64(binary.LittleEndian.Uint64(d[55:63])),
+		Timestamp:     binary.LittleEndian.Uint64(d[63:71]),
+		PegLimit:      int64(binary.LittleEndian.Uint64(d[71:79])),
+		ClientOrderID: node.ForceAlign,
+	}
+	copy(leaf.Padding[:], d[3:7])
+	copy(leaf.Owner[:], d[23:55])
+	return leaf
+}
+
+func (node *AnyNode) innerNode() InnerNode {
+	d := &node.Data
+	inner := InnerNode{
+		Tag:       node.Tag,
+		PrefixLen: binary.LittleEndian.Uint32(d[3:7]),
+		Key: bin.Uint128{
+			Lo: binary.LittleEndian.Uint64(d[7:15]),
+			Hi: binary.LittleEndian.Uint64(d[15:23]),
+		},
+		Children: node.children(),
+		ChildEarliestExpiry: [2]uint64{
+			binary.LittleEndian.Uint64(d[31:39]),
+			binary.LittleEndian.Uint64(d[39:47]),
+		},
+	}
+	copy(inner.Padding[:], d[0:3])
+	copy(inner.Reserved[:], d[47:79])
+	binary.LittleEndian.PutUint64(inner.Reserved[32:], node.ForceAlign)
+	return inner
+}
+
+func (node *AnyNode) children() [2]NodeHandle {
+	d := &node.Data
+	return [2]NodeHandle{
+		NodeHandle(binary.LittleEndian.Uint32(d[23:27])),
+		NodeHandle(binary.LittleEndian.Uint32(d[27:31])),
+	}
+}
+
+// Case returns a copy of the node as an InnerNode or LeafNode, or nil for any
+// other tag. The copies are decoded field by field, so arbitrary account data
+// can't be misread through the node's memory layout.
 func (node *AnyNode) Case() *NodeRef {
 	tag := NodeTag(node.Tag)
 
 	switch tag {
 	case innerNode:
+		inner := node.innerNode()
 		return &NodeRef{
-			Inner: (*InnerNode)(unsafe.Pointer(&node)),
+			Inner: &inner,
 			Leaf:  nil,
 		}
 	case leafNode:
+		leaf := node.leafNode()
 		return &NodeRef{
 			Inner: nil,
-			Leaf:  (*LeafNode)(unsafe.Pointer(&node)),
+			Leaf:  &leaf,
 		}
 	default:
 		return nil
 	}
 }
 
+func (ln *LeafNode) OrderId() OrderId {
+	return OrderIdFromKey(ln.Key)
+}
+
 func (ln *LeafNode) PriceData() uint64 {
-	return uint64(ln.Key.Hi)
+	return ln.OrderId().PriceData()
+}
+
+// IsExpired reports whether the order's time in force has elapsed at nowTs.
+// Orders with a zero time in force never expire.
+func (ln *LeafNode) IsExpired(nowTs uint64) bool {
+	return ln.TimeInForce > 0 && nowTs >= ln.Timestamp+uint64(ln.TimeInForce)
 }
 
 func oraclePeggedPriceOffset(priceData uint64) int64 {
@@ -0,0 +1,119 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	ErrOracleStale      = errors.New("oracle price is stale")
+	ErrOracleConfidence = errors.New("oracle confidence is too wide")
+)
+
+// Layout of a Pyth v2 price account, see pyth-sdk-solana
+const (
+	pythMagic          = 0xa1b2c3d4
+	pythPriceType      = 3
+	pythStatusTrading  = 1
+	pythAggPriceOffset = 208
+	pythAccountSize    = pythAggPriceOffset + 32
+)
+
+var stubOracleDiscriminator = accountDiscriminator("StubOracle")
+
+// StubOracle is the program's test oracle, set by its owner.
+type StubOracle struct {
+	Owner          solana.PublicKey
+	Mint           solana.PublicKey
+	Price          float64
+	LastUpdateTs   int64
+	LastUpdateSlot uint64
+	Deviation      float64
+	Reserved       [104]byte
+}
+
+// OraclePrice is the price an oracle account reports, in ui units of the
+// quote currency of the oracle.
+type OraclePrice struct {
+	Price          float64
+	Deviation      float64 // Confidence interval around Price
+	LastUpdateSlot uint64
+}
+
+// DecodeOracle decodes the price of a Pyth v2 price account or of a
+// StubOracle. Pyth prices must be trading.
+func DecodeOracle(data []byte) (*OraclePrice, error) {
+	if len(data) >= len(stubOracleDiscriminator) && bytes.Equal(data[:len(stubOracleDiscriminator)], stubOracleDiscriminator[:]) {
+		var stub StubOracle
+		if err := decodeAccount(stubOracleDiscriminator, data, &stub); err != nil {
+			return nil, err
+		}
+		return &OraclePrice{Price: stub.Price, Deviation: stub.Deviation, LastUpdateSlot: stub.LastUpdateSlot}, nil
+	}
+
+	if len(data) < pythAccountSize || binary.LittleEndian.Uint32(data) != pythMagic {
+		return nil, errors.New("unknown oracle account")
+	}
+	if binary.LittleEndian.Uint32(data[8:]) != pythPriceType {
+		return nil, errors.New("pyth account is not a price account")
+	}
+	expo := int32(binary.LittleEndian.Uint32(data[20:]))
+	agg := data[pythAggPriceOffset:]
+	price := int64(binary.LittleEndian.Uint64(agg))
+	conf := binary.LittleEndian.Uint64(agg[8:])
+	status := binary.LittleEndian.Uint32(agg[16:])
+	pubSlot := binary.LittleEndian.Uint64(agg[24:])
+	if status != pythStatusTrading {
+		return nil, fmt.Errorf("pyth price status is %d, not trading", status)
+	}
+
+	scale := math.Pow10(int(expo))
+	return &OraclePrice{
+		Price:          float64(price) * scale,
+		Deviation:      float64(conf) * scale,
+		LastUpdateSlot: pubSlot,
+	}, nil
+}
+
+// check rejects oracle prices the program wouldn't use at the given slot:
+// older than MaxStalenessSlots, when it is not negative, or less confident
+// than ConfFilter.
+func (c *OracleConfig) check(price *OraclePrice, slot uint64) error {
+	if !(price.Price > 0) || math.IsInf(price.Price, 0) {
+		return fmt.Errorf("invalid oracle price %g", price.Price)
+	}
+	if c.MaxStalenessSlots >= 0 && slot > price.LastUpdateSlot+uint64(c.MaxStalenessSlots) {
+		return ErrOracleStale
+	}
+	if price.Deviation > c.ConfFilter*price.Price {
+		return ErrOracleConfidence
+	}
+	return nil
+}
+
+// OraclePriceLots returns the price in lots of the market's oracle, the price
+// of OracleA divided by the one of OracleB when the market has both. oracleB
+// must be nil for markets without OracleB.
+func (m *Market) OraclePriceLots(oracleA, oracleB *OraclePrice, slot uint64) (int64, error) {
+	if err := m.OracleConfig.check(oracleA, slot); err != nil {
+		return 0, fmt.Errorf("oracle a: %w", err)
+	}
+	price := oracleA.Price
+	if oracleB != nil {
+		if err := m.OracleConfig.check(oracleB, slot); err != nil {
+			return 0, fmt.Errorf("oracle b: %w", err)
+		}
+		price /= oracleB.Price
+	}
+
+	priceLots := math.Floor(m.UiPriceToLots(price))
+	if !(priceLots >= 1) || priceLots >= math.MaxInt64 {
+		return 0, fmt.Errorf("oracle price %g is out of range in lots", price)
+	}
+	return int64(priceLots), nil
+}
@@ -0,0 +1,159 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// newTestPythPrice lays out a Pyth v2 price account.
+func newTestPythPrice(price int64, conf uint64, expo int32, status uint32, pubSlot uint64) []byte {
+	data := make([]byte, pythAccountSize)
+	binary.LittleEndian.PutUint32(data, pythMagic)
+	binary.LittleEndian.PutUint32(data[4:], 2)
+	binary.LittleEndian.PutUint32(data[8:], pythPriceType)
+	binary.LittleEndian.PutUint32(data[20:], uint32(expo))
+	agg := data[pythAggPriceOffset:]
+	binary.LittleEndian.PutUint64(agg, uint64(price))
+	binary.LittleEndian.PutUint64(agg[8:], conf)
+	binary.LittleEndian.PutUint32(agg[16:], status)
+	binary.LittleEndian.PutUint64(agg[24:], pubSlot)
+	return data
+}
+
+func newTestClock(t testing.TB, slot uint64, unixTimestamp int64) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBinEncoder(buf).Encode(&Clock{Slot: slot, UnixTimestamp: unixTimestamp}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeOracle(t *testing.T) {
+	pyth, err := DecodeOracle(newTestPythPrice(15_012_345_678, 1_000_000, -8, pythStatusTrading, 77))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pyth.Price != 150.12345678 || pyth.Deviation != 0.01 || pyth.LastUpdateSlot != 77 {
+		t.Fatalf("pyth price %+v", pyth)
+	}
+
+	stub, err := DecodeOracle(encodeTestAccount(t, stubOracleDiscriminator, &StubOracle{Price: 1.5, Deviation: 0.1, LastUpdateSlot: 9}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *stub != (OraclePrice{Price: 1.5, Deviation: 0.1, LastUpdateSlot: 9}) {
+		t.Fatalf("stub price %+v", stub)
+	}
+
+	notTrading := newTestPythPrice(100, 1, 0, 2, 77)
+	notPrice := newTestPythPrice(100, 1, 0, pythStatusTrading, 77)
+	binary.LittleEndian.PutUint32(notPrice[8:], 2)
+	for name, data := range map[string][]byte{
+		"halted pyth price": notTrading,
+		"pyth product":      notPrice,
+		"truncated pyth":    newTestPythPrice(100, 1, 0, pythStatusTrading, 77)[:pythAggPriceOffset],
+		"market":            encodeTestAccount(t, marketDiscriminator, newTestMarketAccount()),
+		"empty":             nil,
+	} {
+		if _, err := DecodeOracle(data); err == nil {
+			t.Errorf("decoded a %s", name)
+		}
+	}
+}
+
+func TestOraclePriceLots(t *testing.T) {
+	// SOL-USDC: a ui price of 150 is 0.15 native, with lots of 10^7 base and
+	// 10^3 quote that is 1500 lots
+	market := goldenMarketAccount(9, 6, 10_000_000, 1_000, 0, 0)
+	market.OracleConfig = OracleConfig{ConfFilter: 0.1, MaxStalenessSlots: 10}
+
+	cases := []struct {
+		name     string
+		a, b     OraclePrice
+		slot     uint64
+		expected int64
+		err      error
+	}{
+		{"a only", OraclePrice{Price: 150.0001, LastUpdateSlot: 100}, OraclePrice{}, 110, 1_500, nil},
+		{"a over b", OraclePrice{Price: 150, LastUpdateSlot: 100}, OraclePrice{Price: 0.5, LastUpdateSlot: 100}, 100, 3_000, nil},
+		{"stale a", OraclePrice{Price: 150, LastUpdateSlot: 100}, OraclePrice{}, 111, 0, ErrOracleStale},
+		{"stale b", OraclePrice{Price: 150, LastUpdateSlot: 100}, OraclePrice{Price: 1, LastUpdateSlot: 50}, 100, 0, ErrOracleStale},
+		{"wide a", OraclePrice{Price: 150, Deviation: 15.1, LastUpdateSlot: 100}, OraclePrice{}, 100, 0, ErrOracleConfidence},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var b *OraclePrice
+			if c.b.Price != 0 {
+				b = &c.b
+			}
+			priceLots, err := market.OraclePriceLots(&c.a, b, c.slot)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("error %v, expected %v", err, c.err)
+			}
+			if priceLots != c.expected {
+				t.Fatalf("%d lots, expected %d", priceLots, c.expected)
+			}
+		})
+	}
+
+	// Negative staleness turns the check off, prices below a lot are unusable
+	market.OracleConfig.MaxStalenessSlots = -1
+	if _, err := market.OraclePriceLots(&OraclePrice{Price: 150}, nil, 1_000_000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := market.OraclePriceLots(&OraclePrice{Price: 0.01}, nil, 0); err == nil {
+		t.Fatal("priced a pegged order below one lot")
+	}
+}
+
+func TestUpdateOraclePrice(t *testing.T) {
+	market := newTestMarketAccount()
+	market.OracleA = NonZeroPubkeyOption{Key: solana.PublicKey{8}}
+	market.OracleConfig = OracleConfig{ConfFilter: 0.1, MaxStalenessSlots: 10}
+	obm := newTestMarket(t, market,
+		ask(110, 1),
+		BookOrder{Side: Ask, OraclePegged: true, PriceLots: 2, PegLimit: -1, Quantity: 3, Owner: testOwner},
+	)
+	if keys := obm.OracleAccounts(); len(keys) != 1 || keys[0] != market.OracleA.Key {
+		t.Fatalf("oracle accounts %v", keys)
+	}
+
+	accounts := map[solana.PublicKey][]byte{
+		market.OracleA.Key:       newTestPythPrice(100, 1, 0, pythStatusTrading, 50),
+		solana.SysVarClockPubkey: newTestClock(t, 55, 1),
+	}
+	if err := obm.UpdateOraclePrice(accounts); err != nil {
+		t.Fatal(err)
+	}
+	if obm.OraclePriceLots() == nil || *obm.OraclePriceLots() != 100 {
+		t.Fatalf("oracle price %v, expected 100", obm.OraclePriceLots())
+	}
+	// The pegged ask at 102 fills first
+	quote, err := obm.Quote(&QuoteParams{InAmount: 306, InputMint: testQuoteMint, OutputMint: testBaseMint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.OutAmount != 3 {
+		t.Fatalf("%d out, expected the 3 lots of the pegged ask", quote.OutAmount)
+	}
+
+	// A stale oracle clears the price
+	accounts[solana.SysVarClockPubkey] = newTestClock(t, 61, 1)
+	if err := obm.UpdateOraclePrice(accounts); !errors.Is(err, ErrOracleStale) {
+		t.Fatalf("error %v, expected %v", err, ErrOracleStale)
+	}
+	if obm.OraclePriceLots() != nil {
+		t.Fatalf("stale oracle price %d kept", *obm.OraclePriceLots())
+	}
+
+	delete(accounts, market.OracleA.Key)
+	if err := obm.UpdateOraclePrice(accounts); err == nil {
+		t.Fatal("updated without the oracle account")
+	}
+}
@@ -0,0 +1,122 @@
+package openbookdexgolang
+
+import (
+	"math"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+// OrderId is the 128 bit key of a resting order, LeafNode.Key.
+//
+// The upper 64 bits are the price data: the price in lots for fixed orders,
+// and the offset from the oracle price moved by 2^63 for oracle pegged ones.
+// The lower 64 bits are the order's sequence number, inverted for bids so that
+// earlier orders rank first on both sides. Ids order like their keys in the
+// order trees.
+type OrderId struct {
+	priceData uint64
+	seqData   uint64
+}
+
+// NewFixedOrderId returns the id of a fixed order at priceLots.
+func NewFixedOrderId(side Side, priceLots int64, seqNum uint64) (OrderId, error) {
+	priceData, err := fixedPriceData(priceLots)
+	if err != nil {
+		return OrderId{}, err
+	}
+	return newOrderId(side, priceData, seqNum), nil
+}
+
+// NewPeggedOrderId returns the id of an oracle pegged order priceOffsetLots
+// away from the oracle price.
+func NewPeggedOrderId(side Side, priceOffsetLots int64, seqNum uint64) OrderId {
+	return newOrderId(side, oraclePeggedPriceData(priceOffsetLots), seqNum)
+}
+
+func newOrderId(side Side, priceData uint64, seqNum uint64) OrderId {
+	if side == Bid {
+		seqNum = ^seqNum
+	}
+	return OrderId{priceData: priceData, seqData: seqNum}
+}
+
+// OrderIdFromKey returns the id stored in a node key.
+func OrderIdFromKey(key bin.Uint128) OrderId {
+	return OrderId{priceData: key.Hi, seqData: key.Lo}
+}
+
+// Key returns the id as stored in LeafNode.Key.
+func (id OrderId) Key() bin.Uint128 {
+	return bin.Uint128{Hi: id.priceData, Lo: id.seqData}
+}
+
+func (id OrderId) PriceData() uint64 {
+	return id.priceData
+}
+
+// PriceLots returns the price of a fixed order.
+func (id OrderId) PriceLots() int64 {
+	return int64(id.priceData)
+}
+
+// PegOffsetLots returns the offset from the oracle price of a pegged order.
+func (id OrderId) PegOffsetLots() int64 {
+	return oraclePeggedPriceOffset(id.priceData)
+}
+
+// SeqNum returns the sequence number of an order on the given side.
+func (id OrderId) SeqNum(side Side) uint64 {
+	if side == Bid {
+		return ^id.seqData
+	}
+	return id.seqData
+}
+
+// withPriceData returns the id with the same sequence number at another price.
+func (id OrderId) withPriceData(priceData uint64) OrderId {
+	return OrderId{priceData: priceData, seqData: id.seqData}
+}
+
+// Compare returns -1, 0 or 1 as id is lower than, equal to or higher than
+// other, comparing all 128 bits.
+func (id OrderId) Compare(other OrderId) int {
+	switch {
+	case id.priceData < other.priceData:
+		return -1
+	case id.priceData > other.priceData:
+		return 1
+	case id.seqData < other.seqData:
+		return -1
+	case id.seqData > other.seqData:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (id OrderId) Less(other OrderId) bool {
+	return id.Compare(other) < 0
+}
+
+// String returns the id as a decimal u128, like the program logs it.
+func (id OrderId) String() string {
+	return id.Key().DecimalString()
+}
+
+func (id OrderId) MarshalJSON() ([]byte, error) {
+	return id.Key().MarshalJSON()
+}
+
+func (id *OrderId) UnmarshalJSON(data []byte) error {
+	var key bin.Uint128
+	if err := key.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*id = OrderIdFromKey(key)
+	return nil
+}
+
+func oraclePeggedPriceData(priceOffsetLots int64) uint64 {
+	// Wrapping add logic, the inverse of oraclePeggedPriceOffset
+	return uint64(priceOffsetLots) + (math.MaxUint64/2 + 1)
+}
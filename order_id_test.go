@@ -0,0 +1,115 @@
+package openbookdexgolang
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+func TestOrderIdKeyLayout(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		id   func() (OrderId, error)
+		key  bin.Uint128
+	}{
+		{"fixed ask", func() (OrderId, error) { return NewFixedOrderId(Ask, 100, 7) }, bin.Uint128{Hi: 100, Lo: 7}},
+		{"fixed bid", func() (OrderId, error) { return NewFixedOrderId(Bid, 100, 7) }, bin.Uint128{Hi: 100, Lo: math.MaxUint64 - 7}},
+		{"pegged ask at the oracle", func() (OrderId, error) { return NewPeggedOrderId(Ask, 0, 7), nil }, bin.Uint128{Hi: 1 << 63, Lo: 7}},
+		{"pegged ask below the oracle", func() (OrderId, error) { return NewPeggedOrderId(Ask, -5, 7), nil }, bin.Uint128{Hi: 1<<63 - 5, Lo: 7}},
+		{"pegged bid above the oracle", func() (OrderId, error) { return NewPeggedOrderId(Bid, 5, 7), nil }, bin.Uint128{Hi: 1<<63 + 5, Lo: math.MaxUint64 - 7}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := test.id()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.Key() != test.key {
+				t.Fatalf("key %+v, expected %+v", id.Key(), test.key)
+			}
+			if OrderIdFromKey(test.key) != id {
+				t.Fatalf("id from key %+v, expected %+v", OrderIdFromKey(test.key), id)
+			}
+		})
+	}
+
+	if _, err := NewFixedOrderId(Ask, 0, 7); err == nil {
+		t.Fatal("fixed order id at price 0")
+	}
+}
+
+func TestOrderIdPriceAndSeqNum(t *testing.T) {
+	for _, side := range []Side{Bid, Ask} {
+		fixed, err := NewFixedOrderId(side, 100, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fixed.PriceLots() != 100 || fixed.SeqNum(side) != 7 {
+			t.Fatalf("side %d: fixed price %d seq num %d, expected 100 and 7", side, fixed.PriceLots(), fixed.SeqNum(side))
+		}
+
+		for _, offset := range []int64{math.MinInt64, -5, 0, 5, math.MaxInt64} {
+			pegged := NewPeggedOrderId(side, offset, 7)
+			if pegged.PegOffsetLots() != offset || pegged.SeqNum(side) != 7 {
+				t.Fatalf("side %d: pegged offset %d seq num %d, expected %d and 7", side, pegged.PegOffsetLots(), pegged.SeqNum(side), offset)
+			}
+		}
+	}
+}
+
+func TestOrderIdCompare(t *testing.T) {
+	id := func(side Side, priceLots int64, seqNum uint64) OrderId {
+		id, err := NewFixedOrderId(side, priceLots, seqNum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	// Asks match from the lowest key, bids from the highest: the better price
+	// first, then the earlier order
+	for _, test := range []struct {
+		name   string
+		lower  OrderId
+		higher OrderId
+	}{
+		{"ask same price", id(Ask, 100, 1), id(Ask, 100, 2)},
+		{"ask across prices", id(Ask, 100, 2), id(Ask, 101, 1)},
+		{"bid same price", id(Bid, 100, 2), id(Bid, 100, 1)},
+		{"bid across prices", id(Bid, 100, 1), id(Bid, 101, 2)},
+		{"pegged across offsets", NewPeggedOrderId(Ask, -1, 2), NewPeggedOrderId(Ask, 0, 1)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.lower.Compare(test.higher) != -1 || test.higher.Compare(test.lower) != 1 {
+				t.Fatalf("compare %d and %d, expected -1 and 1", test.lower.Compare(test.higher), test.higher.Compare(test.lower))
+			}
+			if !test.lower.Less(test.higher) || test.higher.Less(test.lower) {
+				t.Fatal("less disagrees with compare")
+			}
+			if test.lower.Compare(test.lower) != 0 || test.lower.Less(test.lower) {
+				t.Fatal("id not equal to itself")
+			}
+		})
+	}
+}
+
+func TestOrderIdJSON(t *testing.T) {
+	for _, id := range []OrderId{NewPeggedOrderId(Bid, -5, 7), NewPeggedOrderId(Ask, 5, math.MaxUint64)} {
+		content, err := json.Marshal(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded OrderId
+		if err := json.Unmarshal(content, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded != id {
+			t.Fatalf("decoded %s from %s, expected %s", decoded, content, id)
+		}
+	}
+
+	if id := OrderIdFromKey(bin.Uint128{Hi: 1, Lo: 2}); id.String() != "18446744073709551618" {
+		t.Fatalf("string %s, expected 18446744073709551618", id)
+	}
+}
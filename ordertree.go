@@ -33,11 +33,14 @@ func (o *OrderTreeNodes) order_tree_type() OrderTreeType {
 	return OrderTreeType(o.OrderTreeType)
 }
 
-func (o *OrderTreeNodes) iter(root *OrderTreeRoot) *OrderTreeIter {
-	return new(o, root)
+func (o *OrderTreeNodes) iter(root *OrderTreeRoot) OrderTreeIter {
+	return newOrderTreeIter(o, root)
 }
 
 func (o *OrderTreeNodes) node(handle NodeHandle) *AnyNode {
+	if int(handle) >= len(o.Nodes) {
+		return nil
+	}
 	node := &o.Nodes[int(handle)]
 	tag := NodeTag(node.Tag)
 	if tag == innerNode || tag == leafNode {
@@ -46,6 +49,12 @@ func (o *OrderTreeNodes) node(handle NodeHandle) *AnyNode {
 	return nil
 }
 
+// LeafNode returns a copy of the leaf stored at handle, as yielded by an
+// OrderTreeIter.
+func (o *OrderTreeNodes) LeafNode(handle NodeHandle) LeafNode {
+	return o.Nodes[int(handle)].leafNode()
+}
+
 type OrderTreeRoot struct {
 	MaybeNode NodeHandle
 	LeafCount uint32
@@ -1,19 +1,29 @@
 package openbookdexgolang
 
-import "unsafe"
+// Inner nodes on a path from the root have strictly increasing prefix lengths
+// over 128 bit keys, so no path holds more than 128 of them.
+const MAX_ORDERTREE_DEPTH = 128
 
+// OrderTreeIter walks the leaves of one order tree in price order, best first.
+//
+// The iterator does not allocate: inner nodes still to visit are kept on a
+// fixed size stack and leaves are returned by handle, to be read with
+// OrderTreeNodes.LeafNode.
+//
+// Corrupt trees end the iteration early instead of panicking, and a tree
+// linking back to its own nodes yields at most MAX_ORDERTREE_NODES leaves.
 type OrderTreeIter struct {
-	OrderTree *OrderTreeNodes // Pointer to OrderTreeNodes
-	Stack     []*InnerNode    // Slice of pointers to InnerNode
-	NextLeaf  *struct {
-		handle NodeHandle
-		leaf   *LeafNode
-	} // Struct to hold NodeHandle and *LeafNode
-	Left  int
-	Right int
+	OrderTree *OrderTreeNodes
+	Stack     [MAX_ORDERTREE_DEPTH]NodeHandle
+	StackLen  int
+	NextLeaf  NodeHandle
+	HasNext   bool
+	Yielded   int
+	Left      int
+	Right     int
 }
 
-func new(orderTree *OrderTreeNodes, root *OrderTreeRoot) *OrderTreeIter {
+func newOrderTreeIter(orderTree *OrderTreeNodes, root *OrderTreeRoot) OrderTreeIter {
 	var left, right int
 	if orderTree.order_tree_type() == Bids {
 		left, right = 1, 0
@@ -21,44 +31,42 @@ func new(orderTree *OrderTreeNodes, root *OrderTreeRoot) *OrderTreeIter {
 		left, right = 0, 1
 	}
 
-	iter := &OrderTreeIter{
+	iter := OrderTreeIter{
 		OrderTree: orderTree,
-		Stack:     []*InnerNode{},
-		NextLeaf:  nil,
 		Left:      left,
 		Right:     right,
 	}
 
 	if r := root.node(); r != nil {
-		iter.NextLeaf = iter.findLeftmostLeaf(*r)
+		iter.NextLeaf, iter.HasNext = iter.findLeftmostLeaf(*r)
 	}
 
 	return iter
 }
 
-func (iter *OrderTreeIter) findLeftmostLeaf(start NodeHandle) *struct {
-	handle NodeHandle
-	leaf   *LeafNode
-} {
+// findLeftmostLeaf descends from start along the left children, pushing the
+// inner nodes passed on the way. It gives up on trees that are deeper than
+// possible or point to nodes that aren't in use.
+func (iter *OrderTreeIter) findLeftmostLeaf(start NodeHandle) (NodeHandle, bool) {
 	current := start
 	for {
 		node := iter.OrderTree.node(current)
 		if node == nil {
-			return nil
+			return 0, false
 		}
 
-		switch n := node.Case().Inner; n {
-		case (*InnerNode)(unsafe.Pointer(&node)):
-			iter.Stack = append(iter.Stack, n)
-			current = n.Children[iter.Left]
-		case nil:
-			return &struct {
-				handle NodeHandle
-				leaf   *LeafNode
-			}{
-				handle: current,
-				leaf:   nil,
+		switch NodeTag(node.Tag) {
+		case innerNode:
+			if iter.StackLen == len(iter.Stack) {
+				return 0, false
 			}
+			iter.Stack[iter.StackLen] = current
+			iter.StackLen++
+			current = node.children()[iter.Left]
+		case leafNode:
+			return current, true
+		default:
+			return 0, false
 		}
 	}
 }
@@ -70,38 +78,33 @@ func (iter *OrderTreeIter) Side() Side {
 	return Ask
 }
 
-// Peek returns the next leaf node if available.
-func (iter *OrderTreeIter) Peek() *struct {
-	handle NodeHandle
-	leaf   *LeafNode
-} {
-	return iter.NextLeaf
+// Peek returns the handle of the next leaf without advancing.
+func (iter *OrderTreeIter) Peek() (NodeHandle, bool) {
+	return iter.NextLeaf, iter.HasNext
 }
 
-func (iter *OrderTreeIter) Next() *struct {
-	handle NodeHandle
-	leaf   *LeafNode
-} {
+// Next returns the handle of the next leaf and advances past it.
+func (iter *OrderTreeIter) Next() (NodeHandle, bool) {
 	// If there's no next leaf, iteration is done
-	if iter.NextLeaf == nil {
-		return nil
+	if !iter.HasNext {
+		return 0, false
 	}
 
 	// Store the current leaf to return
 	currentLeaf := iter.NextLeaf
+	iter.Yielded++
 
 	// Update the next leaf by popping from the stack
-	if len(iter.Stack) == 0 {
-		iter.NextLeaf = nil
+	if iter.StackLen == 0 || iter.Yielded == MAX_ORDERTREE_NODES {
+		iter.HasNext = false
 	} else {
-		inner := iter.Stack[len(iter.Stack)-1]
-		iter.Stack = iter.Stack[:len(iter.Stack)-1] // Pop from stack
-		start := inner.Children[iter.Right]
+		iter.StackLen--
+		inner := iter.OrderTree.node(iter.Stack[iter.StackLen])
+		start := inner.children()[iter.Right]
 
 		// Find the leftmost leaf starting from the right child
-		iter.NextLeaf = iter.findLeftmostLeaf(start)
+		iter.NextLeaf, iter.HasNext = iter.findLeftmostLeaf(start)
 	}
 
-	// Return the current leaf and some placeholder NodeHandle
-	return currentLeaf
+	return currentLeaf, true
 }
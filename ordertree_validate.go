@@ -0,0 +1,238 @@
+package openbookdexgolang
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+// OrderTreeIssue is one broken invariant of an order tree.
+type OrderTreeIssue struct {
+	Handle  NodeHandle
+	Message string
+}
+
+func (i OrderTreeIssue) String() string {
+	return fmt.Sprintf("node %d: %s", i.Handle, i.Message)
+}
+
+// OrderTreeReport is the result of validating order trees. Counts are of the
+// nodes reached, whether valid or not.
+type OrderTreeReport struct {
+	InnerCount int
+	LeafCount  int
+	FreeCount  int
+	Issues     []OrderTreeIssue
+}
+
+func (r *OrderTreeReport) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// Err returns nil for a valid tree, otherwise an error listing every issue.
+func (r *OrderTreeReport) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	messages := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		messages = append(messages, issue.String())
+	}
+	return errors.New("invalid order tree: " + strings.Join(messages, "; "))
+}
+
+func (r *OrderTreeReport) addIssue(handle NodeHandle, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, OrderTreeIssue{Handle: handle, Message: fmt.Sprintf(format, args...)})
+}
+
+// treeValidator checks one OrderTreeNodes, remembering the nodes already
+// reached across all trees and the free list.
+type treeValidator struct {
+	nodes   *OrderTreeNodes
+	report  *OrderTreeReport
+	visited [MAX_ORDERTREE_NODES]bool
+}
+
+// Validate checks the crit-bit invariants of the tree under root and of the
+// free list:
+//
+//   - every handle is below BumpIndex and no node is reached twice
+//   - inner nodes have a prefix shorter than 128 bits, longer than their
+//     parent's, and their children agree with their key on the prefix and
+//     branch on the next bit, left for 0 and right for 1
+//   - ChildEarliestExpiry holds the earliest expiry below each child
+//   - the number of leaves equals root.LeafCount
+//   - the free list holds FreeListLen free nodes, the last one tagged as such
+func (o *OrderTreeNodes) Validate(root *OrderTreeRoot) *OrderTreeReport {
+	v := &treeValidator{nodes: o, report: &OrderTreeReport{}}
+	v.validateTree(root)
+	v.validateFreeList()
+	return v.report
+}
+
+// Validate checks both order trees of the side with OrderTreeNodes.Validate.
+// The trees must not share nodes, and every node below BumpIndex must be in
+// one of them or in the free list.
+func (b *BookSide) Validate() *OrderTreeReport {
+	v := &treeValidator{nodes: &b.Nodes, report: &OrderTreeReport{}}
+	for i := range b.Roots {
+		v.validateTree(&b.Roots[i])
+	}
+	v.validateFreeList()
+	v.validateReached()
+	return v.report
+}
+
+func (v *treeValidator) validateTree(root *OrderTreeRoot) {
+	if root.LeafCount == 0 {
+		return
+	}
+
+	leavesBefore := v.report.LeafCount
+	v.validateNode(root.MaybeNode, nil, 0, 0)
+	if leaves := v.report.LeafCount - leavesBefore; leaves != int(root.LeafCount) {
+		v.report.addIssue(root.MaybeNode, "root has %d leaves, expected %d", leaves, root.LeafCount)
+	}
+}
+
+// claim marks handle as reached, reporting handles out of bounds or reached
+// before.
+func (v *treeValidator) claim(handle NodeHandle) bool {
+	if int(handle) >= MAX_ORDERTREE_NODES || handle >= NodeHandle(v.nodes.BumpIndex) {
+		v.report.addIssue(handle, "handle is not below bump index %d", v.nodes.BumpIndex)
+		return false
+	}
+	if v.visited[handle] {
+		v.report.addIssue(handle, "node is reached more than once")
+		return false
+	}
+	v.visited[handle] = true
+	return true
+}
+
+// validateNode checks the subtree at handle, below a parent with the given
+// key prefix that branched on bit branch to reach it. It returns the earliest
+// expiry of the subtree.
+func (v *treeValidator) validateNode(handle NodeHandle, parent *InnerNode, branch int, depth int) uint64 {
+	if !v.claim(handle) {
+		return math.MaxUint64
+	}
+	if depth > MAX_ORDERTREE_DEPTH {
+		v.report.addIssue(handle, "tree is deeper than %d", MAX_ORDERTREE_DEPTH)
+		return math.MaxUint64
+	}
+
+	node := &v.nodes.Nodes[handle]
+	switch NodeTag(node.Tag) {
+	case innerNode:
+		v.report.InnerCount++
+		inner := node.innerNode()
+		if inner.PrefixLen >= 128 {
+			v.report.addIssue(handle, "prefix length %d is not below 128", inner.PrefixLen)
+			return math.MaxUint64
+		}
+		v.checkKey(handle, inner.Key, parent, branch)
+		if parent != nil && inner.PrefixLen <= parent.PrefixLen {
+			v.report.addIssue(handle, "prefix length %d is not longer than the parent's %d", inner.PrefixLen, parent.PrefixLen)
+		}
+
+		earliestExpiry := uint64(math.MaxUint64)
+		for i, child := range inner.Children {
+			childExpiry := v.validateNode(child, &inner, i, depth+1)
+			if inner.ChildEarliestExpiry[i] != childExpiry {
+				v.report.addIssue(handle, "child %d earliest expiry is %d, expected %d", i, inner.ChildEarliestExpiry[i], childExpiry)
+			}
+			if childExpiry < earliestExpiry {
+				earliestExpiry = childExpiry
+			}
+		}
+		return earliestExpiry
+	case leafNode:
+		v.report.LeafCount++
+		leaf := node.leafNode()
+		v.checkKey(handle, leaf.Key, parent, branch)
+		return leaf.expiry()
+	default:
+		v.report.addIssue(handle, "tag %d is not an inner or leaf node", node.Tag)
+		return math.MaxUint64
+	}
+}
+
+// checkKey reports a key that doesn't match its parent's prefix, or is on the
+// wrong side of it.
+func (v *treeValidator) checkKey(handle NodeHandle, key bin.Uint128, parent *InnerNode, branch int) {
+	if parent == nil {
+		return
+	}
+	if !keyPrefixEqual(key, parent.Key, int(parent.PrefixLen)) {
+		v.report.addIssue(handle, "key doesn't share the parent's %d bit prefix", parent.PrefixLen)
+	}
+	if keyBit(key, int(parent.PrefixLen)) != branch {
+		v.report.addIssue(handle, "key is on the wrong side of the parent, bit %d is not %d", parent.PrefixLen, branch)
+	}
+}
+
+func (v *treeValidator) validateFreeList() {
+	handle := v.nodes.FreeListHead
+	for i := uint32(0); i < v.nodes.FreeListLen; i++ {
+		if !v.claim(handle) {
+			return
+		}
+		v.report.FreeCount++
+
+		node := &v.nodes.Nodes[handle]
+		last := i == v.nodes.FreeListLen-1
+		switch {
+		case last && NodeTag(node.Tag) != lastFreeNode:
+			v.report.addIssue(handle, "last free list node has tag %d", node.Tag)
+		case !last && NodeTag(node.Tag) != freeNode:
+			v.report.addIssue(handle, "free list node %d of %d has tag %d", i, v.nodes.FreeListLen, node.Tag)
+			return
+		}
+		handle = NodeHandle(binary.LittleEndian.Uint32(node.Data[3:7]))
+	}
+}
+
+// validateReached reports the nodes below BumpIndex that no tree or the free
+// list reached, they are leaked.
+func (v *treeValidator) validateReached() {
+	end := min(int(v.nodes.BumpIndex), MAX_ORDERTREE_NODES)
+	for handle := 0; handle < end; handle++ {
+		if !v.visited[handle] {
+			v.report.addIssue(NodeHandle(handle), "node is not reached from a root or the free list")
+		}
+	}
+}
+
+// expiry returns the time the order expires at, or the maximum for orders
+// without a time in force.
+func (ln *LeafNode) expiry() uint64 {
+	if ln.TimeInForce == 0 {
+		return math.MaxUint64
+	}
+	return ln.Timestamp + uint64(ln.TimeInForce)
+}
+
+// keyBit returns bit i of key, counting from the most significant.
+func keyBit(key bin.Uint128, i int) int {
+	if i < 64 {
+		return int(key.Hi>>(63-i)) & 1
+	}
+	return int(key.Lo>>(127-i)) & 1
+}
+
+// keyPrefixEqual reports whether the first n bits of a and b are equal.
+func keyPrefixEqual(a, b bin.Uint128, n int) bool {
+	switch {
+	case n == 0:
+		return true
+	case n <= 64:
+		return a.Hi>>(64-n) == b.Hi>>(64-n)
+	default:
+		return a.Hi == b.Hi && a.Lo>>(128-n) == b.Lo>>(128-n)
+	}
+}
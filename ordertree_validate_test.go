@@ -0,0 +1,157 @@
+package openbookdexgolang
+
+import (
+	"strings"
+	"testing"
+)
+
+// newValidateTestBookSide returns asks laid out as:
+//
+//	0: inner, children 4 and 3 (102)
+//	4: inner, children 2 (100) and 1 (101, expires at 30)
+//	5, 6: the free list
+//
+// with BumpIndex 7.
+func newValidateTestBookSide(t *testing.T) *BookSide {
+	t.Helper()
+	expiring := ask(101, 5)
+	expiring.Timestamp, expiring.TimeInForce = 10, 20
+	book, err := BuildOrderbook([]BookOrder{ask(100, 10), expiring, ask(102, 15)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bookSide := book.Asks
+	head := NodeHandle(bookSide.Nodes.BumpIndex)
+	insertTestNode(t, bookSide, &FreeNode{Tag: uint8(freeNode), Next: head + 1})
+	insertTestNode(t, bookSide, &FreeNode{Tag: uint8(lastFreeNode)})
+	bookSide.Nodes.FreeListHead = head
+	bookSide.Nodes.FreeListLen = 2
+	return bookSide
+}
+
+func insertTestNode(t *testing.T, bookSide *BookSide, v interface{}) NodeHandle {
+	t.Helper()
+	node, err := newAnyNode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle, err := bookSide.Nodes.insertNode(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handle
+}
+
+// setTestInner replaces the inner node at handle with its modified copy.
+func setTestInner(t *testing.T, bookSide *BookSide, handle NodeHandle, modify func(inner *InnerNode)) {
+	t.Helper()
+	inner := bookSide.Nodes.Nodes[handle].innerNode()
+	modify(&inner)
+	node, err := newAnyNode(&inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookSide.Nodes.Nodes[handle] = node
+}
+
+func TestBookSideValidate(t *testing.T) {
+	bookSide := newValidateTestBookSide(t)
+	report := bookSide.Validate()
+	if !report.Valid() {
+		t.Fatal(report.Err())
+	}
+	if report.LeafCount != 3 || report.InnerCount != 2 || report.FreeCount != 2 {
+		t.Fatalf("%d leaves %d inner %d free, expected 3 2 2", report.LeafCount, report.InnerCount, report.FreeCount)
+	}
+}
+
+func TestBookSideValidateCorruption(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		corrupt func(t *testing.T, bookSide *BookSide)
+		handle  NodeHandle
+		message string
+	}{
+		{
+			name: "cycle",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				setTestInner(t, bookSide, 0, func(inner *InnerNode) { inner.Children[1] = 0 })
+			},
+			handle:  0,
+			message: "reached more than once",
+		},
+		{
+			name: "out of range handle",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				setTestInner(t, bookSide, 0, func(inner *InnerNode) { inner.Children[0] = 100 })
+			},
+			handle:  100,
+			message: "not below bump index",
+		},
+		{
+			name: "wrong prefix",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				setTestInner(t, bookSide, 0, func(inner *InnerNode) { inner.Key.Hi ^= 1 << 63 })
+			},
+			handle:  4,
+			message: "doesn't share the parent's",
+		},
+		{
+			name: "wrong branch bit",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				setTestInner(t, bookSide, 0, func(inner *InnerNode) {
+					inner.Children[0], inner.Children[1] = inner.Children[1], inner.Children[0]
+					inner.ChildEarliestExpiry[0], inner.ChildEarliestExpiry[1] = inner.ChildEarliestExpiry[1], inner.ChildEarliestExpiry[0]
+				})
+			},
+			handle:  3,
+			message: "wrong side of the parent",
+		},
+		{
+			name: "stale child earliest expiry",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				setTestInner(t, bookSide, 0, func(inner *InnerNode) { inner.ChildEarliestExpiry[0] = 7 })
+			},
+			handle:  0,
+			message: "child 0 earliest expiry is 7, expected 30",
+		},
+		{
+			name: "wrong leaf count",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				bookSide.Roots[FixedOrderTree].LeafCount = 4
+			},
+			handle:  0,
+			message: "root has 3 leaves, expected 4",
+		},
+		{
+			name: "broken free list tail",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				bookSide.Nodes.Nodes[6].Tag = uint8(freeNode)
+			},
+			handle:  6,
+			message: "last free list node has tag",
+		},
+		{
+			name: "leaked node",
+			corrupt: func(t *testing.T, bookSide *BookSide) {
+				insertTestNode(t, bookSide, &FreeNode{Tag: uint8(freeNode)})
+			},
+			handle:  7,
+			message: "not reached from a root or the free list",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			bookSide := newValidateTestBookSide(t)
+			test.corrupt(t, bookSide)
+
+			report := bookSide.Validate()
+			for _, issue := range report.Issues {
+				if issue.Handle == test.handle && strings.Contains(issue.Message, test.message) {
+					return
+				}
+			}
+			t.Fatalf("issues %v, expected node %d: %s", report.Issues, test.handle, test.message)
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package openbookdexgolang
+
+//go:generate buf generate --template proto/buf.gen.yaml
+
+import (
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+	openbookv1 "github.com/texora/openbook-dex-golang/proto/openbook/v1"
+)
+
+// Converters between the library types and the protobuf messages in
+// proto/openbook/v1.
+
+func uint128ToProto(v bin.Uint128) *openbookv1.Uint128 {
+	return &openbookv1.Uint128{Lo: v.Lo, Hi: v.Hi}
+}
+
+func uint128FromProto(v *openbookv1.Uint128) bin.Uint128 {
+	return bin.Uint128{Lo: v.GetLo(), Hi: v.GetHi()}
+}
+
+func publicKeyFromProto(data []byte) (solana.PublicKey, error) {
+	if len(data) != solana.PublicKeyLength {
+		return solana.PublicKey{}, fmt.Errorf("public key must be %d bytes, got %d", solana.PublicKeyLength, len(data))
+	}
+	return solana.PublicKeyFromBytes(data), nil
+}
+
+func optionalKeyToProto(option NonZeroPubkeyOption) []byte {
+	if !option.IsSome() {
+		return nil
+	}
+	return option.Key.Bytes()
+}
+
+func optionalKeyFromProto(data []byte) (NonZeroPubkeyOption, error) {
+	if len(data) == 0 {
+		return NonZeroPubkeyOption{}, nil
+	}
+	key, err := publicKeyFromProto(data)
+	if err != nil {
+		return NonZeroPubkeyOption{}, err
+	}
+	return NonZeroPubkeyOption{Key: key}, nil
+}
+
+func (ln *LeafNode) ToProto() *openbookv1.LeafNode {
+	return &openbookv1.LeafNode{
+		OwnerSlot:     uint32(ln.OwnerSlot),
+		TimeInForce:   uint32(ln.TimeInForce),
+		Key:           uint128ToProto(ln.Key),
+		Owner:         ln.Owner.Bytes(),
+		Quantity:      ln.Quantity,
+		Timestamp:     ln.Timestamp,
+		PegLimit:      ln.PegLimit,
+		ClientOrderId: ln.ClientOrderID,
+	}
+}
+
+func LeafNodeFromProto(p *openbookv1.LeafNode) (*LeafNode, error) {
+	owner, err := publicKeyFromProto(p.GetOwner())
+	if err != nil {
+		return nil, err
+	}
+	return &LeafNode{
+		Tag:           uint8(leafNode),
+		OwnerSlot:     uint8(p.GetOwnerSlot()),
+		TimeInForce:   uint16(p.GetTimeInForce()),
+		Key:           uint128FromProto(p.GetKey()),
+		Owner:         owner,
+		Quantity:      p.GetQuantity(),
+		Timestamp:     p.GetTimestamp(),
+		PegLimit:      p.GetPegLimit(),
+		ClientOrderID: p.GetClientOrderId(),
+	}, nil
+}
+
+func (b *BookSide) ToProto() (*openbookv1.BookSide, error) {
+	p := &openbookv1.BookSide{
+		OrderTreeType: openbookv1.OrderTreeType_ORDER_TREE_TYPE_BIDS,
+		BumpIndex:     b.Nodes.BumpIndex,
+		FreeListLen:   b.Nodes.FreeListLen,
+		FreeListHead:  uint32(b.Nodes.FreeListHead),
+	}
+	if b.Nodes.order_tree_type() == Asks {
+		p.OrderTreeType = openbookv1.OrderTreeType_ORDER_TREE_TYPE_ASKS
+	}
+	for _, root := range b.Roots {
+		p.Roots = append(p.Roots, &openbookv1.OrderTreeRoot{
+			MaybeNode: uint32(root.MaybeNode),
+			LeafCount: root.LeafCount,
+		})
+	}
+
+	for i := range b.Nodes.Nodes {
+		node := &b.Nodes.Nodes[i]
+		entry := &openbookv1.Node{Handle: uint32(i)}
+		switch NodeTag(node.Tag) {
+		case uninitialized:
+			continue
+		case innerNode:
+			var inner InnerNode
+			if err := node.decode(&inner); err != nil {
+				return nil, err
+			}
+			entry.Node = &openbookv1.Node_Inner{Inner: &openbookv1.InnerNode{
+				PrefixLen:           inner.PrefixLen,
+				Key:                 uint128ToProto(inner.Key),
+				Children:            []uint32{uint32(inner.Children[0]), uint32(inner.Children[1])},
+				ChildEarliestExpiry: inner.ChildEarliestExpiry[:],
+			}}
+		case leafNode:
+			var leaf LeafNode
+			if err := node.decode(&leaf); err != nil {
+				return nil, err
+			}
+			entry.Node = &openbookv1.Node_Leaf{Leaf: leaf.ToProto()}
+		case freeNode, lastFreeNode:
+			var free FreeNode
+			if err := node.decode(&free); err != nil {
+				return nil, err
+			}
+			entry.Node = &openbookv1.Node_Free{Free: &openbookv1.FreeNode{
+				Next: uint32(free.Next),
+				Last: NodeTag(node.Tag) == lastFreeNode,
+			}}
+		default:
+			return nil, fmt.Errorf("node %d has invalid tag %d", i, node.Tag)
+		}
+		p.Nodes = append(p.Nodes, entry)
+	}
+
+	return p, nil
+}
+
+func BookSideFromProto(p *openbookv1.BookSide) (*BookSide, error) {
+	var bookSide BookSide
+	switch p.GetOrderTreeType() {
+	case openbookv1.OrderTreeType_ORDER_TREE_TYPE_BIDS:
+		bookSide.Nodes.OrderTreeType = uint8(Bids)
+	case openbookv1.OrderTreeType_ORDER_TREE_TYPE_ASKS:
+		bookSide.Nodes.OrderTreeType = uint8(Asks)
+	default:
+		return nil, fmt.Errorf("invalid order tree type %d", p.GetOrderTreeType())
+	}
+	if len(p.GetRoots()) != len(bookSide.Roots) {
+		return nil, fmt.Errorf("book side must have %d roots", len(bookSide.Roots))
+	}
+	for i, root := range p.GetRoots() {
+		bookSide.Roots[i] = OrderTreeRoot{MaybeNode: NodeHandle(root.GetMaybeNode()), LeafCount: root.GetLeafCount()}
+	}
+	bookSide.Nodes.BumpIndex = p.GetBumpIndex()
+	bookSide.Nodes.FreeListLen = p.GetFreeListLen()
+	bookSide.Nodes.FreeListHead = NodeHandle(p.GetFreeListHead())
+
+	for _, entry := range p.GetNodes() {
+		if entry.GetHandle() >= MAX_ORDERTREE_NODES {
+			return nil, fmt.Errorf("node handle %d out of range", entry.GetHandle())
+		}
+
+		var node AnyNode
+		var err error
+		switch n := entry.GetNode().(type) {
+		case *openbookv1.Node_Inner:
+			if len(n.Inner.GetChildren()) != 2 || len(n.Inner.GetChildEarliestExpiry()) != 2 {
+				return nil, fmt.Errorf("inner node %d must have 2 children", entry.GetHandle())
+			}
+			node, err = newAnyNode(&InnerNode{
+				Tag:                 uint8(innerNode),
+				PrefixLen:           n.Inner.GetPrefixLen(),
+				Key:                 uint128FromProto(n.Inner.GetKey()),
+				Children:            [2]NodeHandle{NodeHandle(n.Inner.Children[0]), NodeHandle(n.Inner.Children[1])},
+				ChildEarliestExpiry: [2]uint64{n.Inner.ChildEarliestExpiry[0], n.Inner.ChildEarliestExpiry[1]},
+			})
+		case *openbookv1.Node_Leaf:
+			var leaf *LeafNode
+			leaf, err = LeafNodeFromProto(n.Leaf)
+			if err == nil {
+				node, err = newAnyNode(leaf)
+			}
+		case *openbookv1.Node_Free:
+			tag := freeNode
+			if n.Free.GetLast() {
+				tag = lastFreeNode
+			}
+			node, err = newAnyNode(&FreeNode{Tag: uint8(tag), Next: NodeHandle(n.Free.GetNext())})
+		default:
+			return nil, fmt.Errorf("node %d is empty", entry.GetHandle())
+		}
+		if err != nil {
+			return nil, err
+		}
+		bookSide.Nodes.Nodes[entry.GetHandle()] = node
+	}
+
+	return &bookSide, nil
+}
+
+func (o *Orderbook) ToProto() (*openbookv1.Orderbook, error) {
+	if o.Bids == nil || o.Asks == nil {
+		return nil, errors.New("orderbook must have both sides")
+	}
+	bids, err := o.Bids.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	asks, err := o.Asks.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return &openbookv1.Orderbook{Bids: bids, Asks: asks}, nil
+}
+
+func OrderbookFromProto(p *openbookv1.Orderbook) (*Orderbook, error) {
+	bids, err := BookSideFromProto(p.GetBids())
+	if err != nil {
+		return nil, err
+	}
+	asks, err := BookSideFromProto(p.GetAsks())
+	if err != nil {
+		return nil, err
+	}
+	return &Orderbook{Bids: bids, Asks: asks}, nil
+}
+
+func (a *Amounts) ToProto() *openbookv1.Amounts {
+	return &openbookv1.Amounts{
+		TotalBaseTakenNative:  a.TotalBaseTakenNative,
+		TotalQuoteTakenNative: a.TotalQuoteTakenNative,
+		Fee:                   a.Fee,
+		NotEnoughLiquidity:    a.NotEnoughLiquidity,
+	}
+}
+
+func AmountsFromProto(p *openbookv1.Amounts) *Amounts {
+	return &Amounts{
+		TotalBaseTakenNative:  p.GetTotalBaseTakenNative(),
+		TotalQuoteTakenNative: p.GetTotalQuoteTakenNative(),
+		Fee:                   p.GetFee(),
+		NotEnoughLiquidity:    p.GetNotEnoughLiquidity(),
+	}
+}
+
+func (q *Quote) ToProto() *openbookv1.Quote {
+	return &openbookv1.Quote{
+		NotEnoughLiquidity: q.NotEnoughLiquidity,
+		MinInAmount:        q.MinInAmount,
+		MinOutAmount:       q.MinOutAmount,
+		InAmount:           q.InAmount,
+		OutAmount:          q.OutAmount,
+		FeeAmount:          q.FeeAmount,
+		FeeMint:            q.FeeMint.Bytes(),
+		FeePct:             q.FeePct.String(),
+	}
+}
+
+func QuoteFromProto(p *openbookv1.Quote) (*Quote, error) {
+	feeMint, err := publicKeyFromProto(p.GetFeeMint())
+	if err != nil {
+		return nil, err
+	}
+	feePct, err := ParseDecimal(p.GetFeePct())
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{
+		NotEnoughLiquidity: p.GetNotEnoughLiquidity(),
+		MinInAmount:        p.MinInAmount,
+		MinOutAmount:       p.MinOutAmount,
+		InAmount:           p.GetInAmount(),
+		OutAmount:          p.GetOutAmount(),
+		FeeAmount:          p.GetFeeAmount(),
+		FeeMint:            feeMint,
+		FeePct:             feePct,
+	}, nil
+}
+
+func (m *Market) ToProto() *openbookv1.Market {
+	return &openbookv1.Market{
+		Bump:               uint32(m.Bump),
+		BaseDecimals:       uint32(m.BaseDecimals),
+		QuoteDecimals:      uint32(m.QuoteDecimals),
+		MarketAuthority:    m.MarketAuthority.Bytes(),
+		TimeExpiry:         m.TimeExpiry,
+		CollectFeeAdmin:    m.CollectFeeAdmin.Bytes(),
+		OpenOrdersAdmin:    optionalKeyToProto(m.OpenOrdersAdmin),
+		ConsumeEventsAdmin: optionalKeyToProto(m.ConsumeEventsAdmin),
+		CloseMarketAdmin:   optionalKeyToProto(m.CloseMarketAdmin),
+		Name:               m.NameString(),
+		Bids:               m.Bids.Bytes(),
+		Asks:               m.Asks.Bytes(),
+		EventHeap:          m.EventHeap.Bytes(),
+		OracleA:            optionalKeyToProto(m.OracleA),
+		OracleB:            optionalKeyToProto(m.OracleB),
+		OracleConfig: &openbookv1.OracleConfig{
+			ConfFilter:        m.OracleConfig.ConfFilter,
+			MaxStalenessSlots: m.OracleConfig.MaxStalenessSlots,
+		},
+		QuoteLotSize:           m.QuoteLotSize,
+		BaseLotSize:            m.BaseLotSize,
+		SeqNum:                 m.SeqNum,
+		RegistrationTime:       m.RegistrationTime,
+		MakerFee:               m.MakerFee,
+		TakerFee:               m.TakerFee,
+		FeesAccrued:            uint128ToProto(m.FeesAccrued),
+		FeesToReferrers:        uint128ToProto(m.FeesToReferrers),
+		ReferrerRebatesAccrued: m.ReferrerRebatesAccrued,
+		FeesAvailable:          m.FeesAvailable,
+		MakerVolume:            uint128ToProto(m.MakerVolume),
+		TakerVolumeWoOo:        uint128ToProto(m.TakerVolumeWoOo),
+		BaseMint:               m.BaseMint.Bytes(),
+		QuoteMint:              m.QuoteMint.Bytes(),
+		MarketBaseVault:        m.MarketBaseVault.Bytes(),
+		BaseDepositTotal:       m.BaseDepositTotal,
+		MarketQuoteVault:       m.MarketQuoteVault.Bytes(),
+		QuoteDepositTotal:      m.QuoteDepositTotal,
+	}
+}
+
+func MarketFromProto(p *openbookv1.Market) (*Market, error) {
+	market := Market{
+		Bump:          uint8(p.GetBump()),
+		BaseDecimals:  uint8(p.GetBaseDecimals()),
+		QuoteDecimals: uint8(p.GetQuoteDecimals()),
+		TimeExpiry:    p.GetTimeExpiry(),
+		OracleConfig: OracleConfig{
+			ConfFilter:        p.GetOracleConfig().GetConfFilter(),
+			MaxStalenessSlots: p.GetOracleConfig().GetMaxStalenessSlots(),
+		},
+		QuoteLotSize:           p.GetQuoteLotSize(),
+		BaseLotSize:            p.GetBaseLotSize(),
+		SeqNum:                 p.GetSeqNum(),
+		RegistrationTime:       p.GetRegistrationTime(),
+		MakerFee:               p.GetMakerFee(),
+		TakerFee:               p.GetTakerFee(),
+		FeesAccrued:            uint128FromProto(p.GetFeesAccrued()),
+		FeesToReferrers:        uint128FromProto(p.GetFeesToReferrers()),
+		ReferrerRebatesAccrued: p.GetReferrerRebatesAccrued(),
+		FeesAvailable:          p.GetFeesAvailable(),
+		MakerVolume:            uint128FromProto(p.GetMakerVolume()),
+		TakerVolumeWoOo:        uint128FromProto(p.GetTakerVolumeWoOo()),
+		BaseDepositTotal:       p.GetBaseDepositTotal(),
+		QuoteDepositTotal:      p.GetQuoteDepositTotal(),
+	}
+	if len(p.GetName()) > len(market.Name) {
+		return nil, errors.New("market name is too long")
+	}
+	copy(market.Name[:], p.GetName())
+
+	keys := []struct {
+		dst  *solana.PublicKey
+		data []byte
+	}{
+		{&market.MarketAuthority, p.GetMarketAuthority()},
+		{&market.CollectFeeAdmin, p.GetCollectFeeAdmin()},
+		{&market.Bids, p.GetBids()},
+		{&market.Asks, p.GetAsks()},
+		{&market.EventHeap, p.GetEventHeap()},
+		{&market.BaseMint, p.GetBaseMint()},
+		{&market.QuoteMint, p.GetQuoteMint()},
+		{&market.MarketBaseVault, p.GetMarketBaseVault()},
+		{&market.MarketQuoteVault, p.GetMarketQuoteVault()},
+	}
+	for _, key := range keys {
+		value, err := publicKeyFromProto(key.data)
+		if err != nil {
+			return nil, err
+		}
+		*key.dst = value
+	}
+
+	options := []struct {
+		dst  *NonZeroPubkeyOption
+		data []byte
+	}{
+		{&market.OpenOrdersAdmin, p.GetOpenOrdersAdmin()},
+		{&market.ConsumeEventsAdmin, p.GetConsumeEventsAdmin()},
+		{&market.CloseMarketAdmin, p.GetCloseMarketAdmin()},
+		{&market.OracleA, p.GetOracleA()},
+		{&market.OracleB, p.GetOracleB()},
+	}
+	for _, option := range options {
+		value, err := optionalKeyFromProto(option.data)
+		if err != nil {
+			return nil, err
+		}
+		*option.dst = value
+	}
+
+	return &market, nil
+}
@@ -0,0 +1,1640 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: openbook/v1/openbook.proto
+
+package openbookv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OrderTreeType int32
+
+const (
+	OrderTreeType_ORDER_TREE_TYPE_BIDS OrderTreeType = 0
+	OrderTreeType_ORDER_TREE_TYPE_ASKS OrderTreeType = 1
+)
+
+// Enum value maps for OrderTreeType.
+var (
+	OrderTreeType_name = map[int32]string{
+		0: "ORDER_TREE_TYPE_BIDS",
+		1: "ORDER_TREE_TYPE_ASKS",
+	}
+	OrderTreeType_value = map[string]int32{
+		"ORDER_TREE_TYPE_BIDS": 0,
+		"ORDER_TREE_TYPE_ASKS": 1,
+	}
+)
+
+func (x OrderTreeType) Enum() *OrderTreeType {
+	p := new(OrderTreeType)
+	*p = x
+	return p
+}
+
+func (x OrderTreeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderTreeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_openbook_v1_openbook_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderTreeType) Type() protoreflect.EnumType {
+	return &file_openbook_v1_openbook_proto_enumTypes[0]
+}
+
+func (x OrderTreeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderTreeType.Descriptor instead.
+func (OrderTreeType) EnumDescriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{0}
+}
+
+// Little endian halves of an unsigned 128 bit integer.
+type Uint128 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lo uint64 `protobuf:"fixed64,1,opt,name=lo,proto3" json:"lo,omitempty"`
+	Hi uint64 `protobuf:"fixed64,2,opt,name=hi,proto3" json:"hi,omitempty"`
+}
+
+func (x *Uint128) Reset() {
+	*x = Uint128{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Uint128) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Uint128) ProtoMessage() {}
+
+func (x *Uint128) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Uint128.ProtoReflect.Descriptor instead.
+func (*Uint128) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Uint128) GetLo() uint64 {
+	if x != nil {
+		return x.Lo
+	}
+	return 0
+}
+
+func (x *Uint128) GetHi() uint64 {
+	if x != nil {
+		return x.Hi
+	}
+	return 0
+}
+
+type LeafNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OwnerSlot   uint32   `protobuf:"varint,1,opt,name=owner_slot,json=ownerSlot,proto3" json:"owner_slot,omitempty"`
+	TimeInForce uint32   `protobuf:"varint,2,opt,name=time_in_force,json=timeInForce,proto3" json:"time_in_force,omitempty"`
+	Key         *Uint128 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// 32 byte public key
+	Owner         []byte `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	Quantity      int64  `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Timestamp     uint64 `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	PegLimit      int64  `protobuf:"varint,7,opt,name=peg_limit,json=pegLimit,proto3" json:"peg_limit,omitempty"`
+	ClientOrderId uint64 `protobuf:"varint,8,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+}
+
+func (x *LeafNode) Reset() {
+	*x = LeafNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeafNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeafNode) ProtoMessage() {}
+
+func (x *LeafNode) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeafNode.ProtoReflect.Descriptor instead.
+func (*LeafNode) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LeafNode) GetOwnerSlot() uint32 {
+	if x != nil {
+		return x.OwnerSlot
+	}
+	return 0
+}
+
+func (x *LeafNode) GetTimeInForce() uint32 {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return 0
+}
+
+func (x *LeafNode) GetKey() *Uint128 {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *LeafNode) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *LeafNode) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LeafNode) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LeafNode) GetPegLimit() int64 {
+	if x != nil {
+		return x.PegLimit
+	}
+	return 0
+}
+
+func (x *LeafNode) GetClientOrderId() uint64 {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return 0
+}
+
+type InnerNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PrefixLen           uint32   `protobuf:"varint,1,opt,name=prefix_len,json=prefixLen,proto3" json:"prefix_len,omitempty"`
+	Key                 *Uint128 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Children            []uint32 `protobuf:"varint,3,rep,packed,name=children,proto3" json:"children,omitempty"`
+	ChildEarliestExpiry []uint64 `protobuf:"varint,4,rep,packed,name=child_earliest_expiry,json=childEarliestExpiry,proto3" json:"child_earliest_expiry,omitempty"`
+}
+
+func (x *InnerNode) Reset() {
+	*x = InnerNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InnerNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InnerNode) ProtoMessage() {}
+
+func (x *InnerNode) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InnerNode.ProtoReflect.Descriptor instead.
+func (*InnerNode) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InnerNode) GetPrefixLen() uint32 {
+	if x != nil {
+		return x.PrefixLen
+	}
+	return 0
+}
+
+func (x *InnerNode) GetKey() *Uint128 {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *InnerNode) GetChildren() []uint32 {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *InnerNode) GetChildEarliestExpiry() []uint64 {
+	if x != nil {
+		return x.ChildEarliestExpiry
+	}
+	return nil
+}
+
+type FreeNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Next uint32 `protobuf:"varint,1,opt,name=next,proto3" json:"next,omitempty"`
+	Last bool   `protobuf:"varint,2,opt,name=last,proto3" json:"last,omitempty"`
+}
+
+func (x *FreeNode) Reset() {
+	*x = FreeNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreeNode) ProtoMessage() {}
+
+func (x *FreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreeNode.ProtoReflect.Descriptor instead.
+func (*FreeNode) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FreeNode) GetNext() uint32 {
+	if x != nil {
+		return x.Next
+	}
+	return 0
+}
+
+func (x *FreeNode) GetLast() bool {
+	if x != nil {
+		return x.Last
+	}
+	return false
+}
+
+// A used slot of the order tree node array.
+type Node struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Handle uint32 `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// Types that are assignable to Node:
+	//	*Node_Inner
+	//	*Node_Leaf
+	//	*Node_Free
+	Node isNode_Node `protobuf_oneof:"node"`
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Node) GetHandle() uint32 {
+	if x != nil {
+		return x.Handle
+	}
+	return 0
+}
+
+func (m *Node) GetNode() isNode_Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (x *Node) GetInner() *InnerNode {
+	if x, ok := x.GetNode().(*Node_Inner); ok {
+		return x.Inner
+	}
+	return nil
+}
+
+func (x *Node) GetLeaf() *LeafNode {
+	if x, ok := x.GetNode().(*Node_Leaf); ok {
+		return x.Leaf
+	}
+	return nil
+}
+
+func (x *Node) GetFree() *FreeNode {
+	if x, ok := x.GetNode().(*Node_Free); ok {
+		return x.Free
+	}
+	return nil
+}
+
+type isNode_Node interface {
+	isNode_Node()
+}
+
+type Node_Inner struct {
+	Inner *InnerNode `protobuf:"bytes,2,opt,name=inner,proto3,oneof"`
+}
+
+type Node_Leaf struct {
+	Leaf *LeafNode `protobuf:"bytes,3,opt,name=leaf,proto3,oneof"`
+}
+
+type Node_Free struct {
+	Free *FreeNode `protobuf:"bytes,4,opt,name=free,proto3,oneof"`
+}
+
+func (*Node_Inner) isNode_Node() {}
+
+func (*Node_Leaf) isNode_Node() {}
+
+func (*Node_Free) isNode_Node() {}
+
+type OrderTreeRoot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MaybeNode uint32 `protobuf:"varint,1,opt,name=maybe_node,json=maybeNode,proto3" json:"maybe_node,omitempty"`
+	LeafCount uint32 `protobuf:"varint,2,opt,name=leaf_count,json=leafCount,proto3" json:"leaf_count,omitempty"`
+}
+
+func (x *OrderTreeRoot) Reset() {
+	*x = OrderTreeRoot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderTreeRoot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderTreeRoot) ProtoMessage() {}
+
+func (x *OrderTreeRoot) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderTreeRoot.ProtoReflect.Descriptor instead.
+func (*OrderTreeRoot) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderTreeRoot) GetMaybeNode() uint32 {
+	if x != nil {
+		return x.MaybeNode
+	}
+	return 0
+}
+
+func (x *OrderTreeRoot) GetLeafCount() uint32 {
+	if x != nil {
+		return x.LeafCount
+	}
+	return 0
+}
+
+type BookSide struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderTreeType OrderTreeType `protobuf:"varint,1,opt,name=order_tree_type,json=orderTreeType,proto3,enum=openbook.v1.OrderTreeType" json:"order_tree_type,omitempty"`
+	// Fixed and oracle pegged tree roots
+	Roots        []*OrderTreeRoot `protobuf:"bytes,2,rep,name=roots,proto3" json:"roots,omitempty"`
+	BumpIndex    uint32           `protobuf:"varint,3,opt,name=bump_index,json=bumpIndex,proto3" json:"bump_index,omitempty"`
+	FreeListLen  uint32           `protobuf:"varint,4,opt,name=free_list_len,json=freeListLen,proto3" json:"free_list_len,omitempty"`
+	FreeListHead uint32           `protobuf:"varint,5,opt,name=free_list_head,json=freeListHead,proto3" json:"free_list_head,omitempty"`
+	Nodes        []*Node          `protobuf:"bytes,6,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *BookSide) Reset() {
+	*x = BookSide{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BookSide) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookSide) ProtoMessage() {}
+
+func (x *BookSide) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookSide.ProtoReflect.Descriptor instead.
+func (*BookSide) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BookSide) GetOrderTreeType() OrderTreeType {
+	if x != nil {
+		return x.OrderTreeType
+	}
+	return OrderTreeType_ORDER_TREE_TYPE_BIDS
+}
+
+func (x *BookSide) GetRoots() []*OrderTreeRoot {
+	if x != nil {
+		return x.Roots
+	}
+	return nil
+}
+
+func (x *BookSide) GetBumpIndex() uint32 {
+	if x != nil {
+		return x.BumpIndex
+	}
+	return 0
+}
+
+func (x *BookSide) GetFreeListLen() uint32 {
+	if x != nil {
+		return x.FreeListLen
+	}
+	return 0
+}
+
+func (x *BookSide) GetFreeListHead() uint32 {
+	if x != nil {
+		return x.FreeListHead
+	}
+	return 0
+}
+
+func (x *BookSide) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type Orderbook struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bids *BookSide `protobuf:"bytes,1,opt,name=bids,proto3" json:"bids,omitempty"`
+	Asks *BookSide `protobuf:"bytes,2,opt,name=asks,proto3" json:"asks,omitempty"`
+}
+
+func (x *Orderbook) Reset() {
+	*x = Orderbook{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Orderbook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Orderbook) ProtoMessage() {}
+
+func (x *Orderbook) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Orderbook.ProtoReflect.Descriptor instead.
+func (*Orderbook) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Orderbook) GetBids() *BookSide {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *Orderbook) GetAsks() *BookSide {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+type Amounts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalBaseTakenNative  uint64 `protobuf:"varint,1,opt,name=total_base_taken_native,json=totalBaseTakenNative,proto3" json:"total_base_taken_native,omitempty"`
+	TotalQuoteTakenNative uint64 `protobuf:"varint,2,opt,name=total_quote_taken_native,json=totalQuoteTakenNative,proto3" json:"total_quote_taken_native,omitempty"`
+	Fee                   uint64 `protobuf:"varint,3,opt,name=fee,proto3" json:"fee,omitempty"`
+	NotEnoughLiquidity    bool   `protobuf:"varint,4,opt,name=not_enough_liquidity,json=notEnoughLiquidity,proto3" json:"not_enough_liquidity,omitempty"`
+}
+
+func (x *Amounts) Reset() {
+	*x = Amounts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Amounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Amounts) ProtoMessage() {}
+
+func (x *Amounts) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Amounts.ProtoReflect.Descriptor instead.
+func (*Amounts) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Amounts) GetTotalBaseTakenNative() uint64 {
+	if x != nil {
+		return x.TotalBaseTakenNative
+	}
+	return 0
+}
+
+func (x *Amounts) GetTotalQuoteTakenNative() uint64 {
+	if x != nil {
+		return x.TotalQuoteTakenNative
+	}
+	return 0
+}
+
+func (x *Amounts) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Amounts) GetNotEnoughLiquidity() bool {
+	if x != nil {
+		return x.NotEnoughLiquidity
+	}
+	return false
+}
+
+type Quote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NotEnoughLiquidity bool    `protobuf:"varint,1,opt,name=not_enough_liquidity,json=notEnoughLiquidity,proto3" json:"not_enough_liquidity,omitempty"`
+	MinInAmount        *uint64 `protobuf:"varint,2,opt,name=min_in_amount,json=minInAmount,proto3,oneof" json:"min_in_amount,omitempty"`
+	MinOutAmount       *uint64 `protobuf:"varint,3,opt,name=min_out_amount,json=minOutAmount,proto3,oneof" json:"min_out_amount,omitempty"`
+	InAmount           uint64  `protobuf:"varint,4,opt,name=in_amount,json=inAmount,proto3" json:"in_amount,omitempty"`
+	OutAmount          uint64  `protobuf:"varint,5,opt,name=out_amount,json=outAmount,proto3" json:"out_amount,omitempty"`
+	FeeAmount          uint64  `protobuf:"varint,6,opt,name=fee_amount,json=feeAmount,proto3" json:"fee_amount,omitempty"`
+	FeeMint            []byte  `protobuf:"bytes,7,opt,name=fee_mint,json=feeMint,proto3" json:"fee_mint,omitempty"`
+	// Decimal string, e.g. "0.0004"
+	FeePct string `protobuf:"bytes,8,opt,name=fee_pct,json=feePct,proto3" json:"fee_pct,omitempty"`
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Quote) GetNotEnoughLiquidity() bool {
+	if x != nil {
+		return x.NotEnoughLiquidity
+	}
+	return false
+}
+
+func (x *Quote) GetMinInAmount() uint64 {
+	if x != nil && x.MinInAmount != nil {
+		return *x.MinInAmount
+	}
+	return 0
+}
+
+func (x *Quote) GetMinOutAmount() uint64 {
+	if x != nil && x.MinOutAmount != nil {
+		return *x.MinOutAmount
+	}
+	return 0
+}
+
+func (x *Quote) GetInAmount() uint64 {
+	if x != nil {
+		return x.InAmount
+	}
+	return 0
+}
+
+func (x *Quote) GetOutAmount() uint64 {
+	if x != nil {
+		return x.OutAmount
+	}
+	return 0
+}
+
+func (x *Quote) GetFeeAmount() uint64 {
+	if x != nil {
+		return x.FeeAmount
+	}
+	return 0
+}
+
+func (x *Quote) GetFeeMint() []byte {
+	if x != nil {
+		return x.FeeMint
+	}
+	return nil
+}
+
+func (x *Quote) GetFeePct() string {
+	if x != nil {
+		return x.FeePct
+	}
+	return ""
+}
+
+type OracleConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConfFilter        float64 `protobuf:"fixed64,1,opt,name=conf_filter,json=confFilter,proto3" json:"conf_filter,omitempty"`
+	MaxStalenessSlots int64   `protobuf:"varint,2,opt,name=max_staleness_slots,json=maxStalenessSlots,proto3" json:"max_staleness_slots,omitempty"`
+}
+
+func (x *OracleConfig) Reset() {
+	*x = OracleConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OracleConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OracleConfig) ProtoMessage() {}
+
+func (x *OracleConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OracleConfig.ProtoReflect.Descriptor instead.
+func (*OracleConfig) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OracleConfig) GetConfFilter() float64 {
+	if x != nil {
+		return x.ConfFilter
+	}
+	return 0
+}
+
+func (x *OracleConfig) GetMaxStalenessSlots() int64 {
+	if x != nil {
+		return x.MaxStalenessSlots
+	}
+	return 0
+}
+
+// Public keys are 32 bytes, optional keys are empty when unset.
+type Market struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bump                   uint32        `protobuf:"varint,1,opt,name=bump,proto3" json:"bump,omitempty"`
+	BaseDecimals           uint32        `protobuf:"varint,2,opt,name=base_decimals,json=baseDecimals,proto3" json:"base_decimals,omitempty"`
+	QuoteDecimals          uint32        `protobuf:"varint,3,opt,name=quote_decimals,json=quoteDecimals,proto3" json:"quote_decimals,omitempty"`
+	MarketAuthority        []byte        `protobuf:"bytes,4,opt,name=market_authority,json=marketAuthority,proto3" json:"market_authority,omitempty"`
+	TimeExpiry             int64         `protobuf:"varint,5,opt,name=time_expiry,json=timeExpiry,proto3" json:"time_expiry,omitempty"`
+	CollectFeeAdmin        []byte        `protobuf:"bytes,6,opt,name=collect_fee_admin,json=collectFeeAdmin,proto3" json:"collect_fee_admin,omitempty"`
+	OpenOrdersAdmin        []byte        `protobuf:"bytes,7,opt,name=open_orders_admin,json=openOrdersAdmin,proto3" json:"open_orders_admin,omitempty"`
+	ConsumeEventsAdmin     []byte        `protobuf:"bytes,8,opt,name=consume_events_admin,json=consumeEventsAdmin,proto3" json:"consume_events_admin,omitempty"`
+	CloseMarketAdmin       []byte        `protobuf:"bytes,9,opt,name=close_market_admin,json=closeMarketAdmin,proto3" json:"close_market_admin,omitempty"`
+	Name                   string        `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"`
+	Bids                   []byte        `protobuf:"bytes,11,opt,name=bids,proto3" json:"bids,omitempty"`
+	Asks                   []byte        `protobuf:"bytes,12,opt,name=asks,proto3" json:"asks,omitempty"`
+	EventHeap              []byte        `protobuf:"bytes,13,opt,name=event_heap,json=eventHeap,proto3" json:"event_heap,omitempty"`
+	OracleA                []byte        `protobuf:"bytes,14,opt,name=oracle_a,json=oracleA,proto3" json:"oracle_a,omitempty"`
+	OracleB                []byte        `protobuf:"bytes,15,opt,name=oracle_b,json=oracleB,proto3" json:"oracle_b,omitempty"`
+	OracleConfig           *OracleConfig `protobuf:"bytes,16,opt,name=oracle_config,json=oracleConfig,proto3" json:"oracle_config,omitempty"`
+	QuoteLotSize           int64         `protobuf:"varint,17,opt,name=quote_lot_size,json=quoteLotSize,proto3" json:"quote_lot_size,omitempty"`
+	BaseLotSize            int64         `protobuf:"varint,18,opt,name=base_lot_size,json=baseLotSize,proto3" json:"base_lot_size,omitempty"`
+	SeqNum                 uint64        `protobuf:"varint,19,opt,name=seq_num,json=seqNum,proto3" json:"seq_num,omitempty"`
+	RegistrationTime       int64         `protobuf:"varint,20,opt,name=registration_time,json=registrationTime,proto3" json:"registration_time,omitempty"`
+	MakerFee               int64         `protobuf:"varint,21,opt,name=maker_fee,json=makerFee,proto3" json:"maker_fee,omitempty"`
+	TakerFee               int64         `protobuf:"varint,22,opt,name=taker_fee,json=takerFee,proto3" json:"taker_fee,omitempty"`
+	FeesAccrued            *Uint128      `protobuf:"bytes,23,opt,name=fees_accrued,json=feesAccrued,proto3" json:"fees_accrued,omitempty"`
+	FeesToReferrers        *Uint128      `protobuf:"bytes,24,opt,name=fees_to_referrers,json=feesToReferrers,proto3" json:"fees_to_referrers,omitempty"`
+	ReferrerRebatesAccrued uint64        `protobuf:"varint,25,opt,name=referrer_rebates_accrued,json=referrerRebatesAccrued,proto3" json:"referrer_rebates_accrued,omitempty"`
+	FeesAvailable          uint64        `protobuf:"varint,26,opt,name=fees_available,json=feesAvailable,proto3" json:"fees_available,omitempty"`
+	MakerVolume            *Uint128      `protobuf:"bytes,27,opt,name=maker_volume,json=makerVolume,proto3" json:"maker_volume,omitempty"`
+	TakerVolumeWoOo        *Uint128      `protobuf:"bytes,28,opt,name=taker_volume_wo_oo,json=takerVolumeWoOo,proto3" json:"taker_volume_wo_oo,omitempty"`
+	BaseMint               []byte        `protobuf:"bytes,29,opt,name=base_mint,json=baseMint,proto3" json:"base_mint,omitempty"`
+	QuoteMint              []byte        `protobuf:"bytes,30,opt,name=quote_mint,json=quoteMint,proto3" json:"quote_mint,omitempty"`
+	MarketBaseVault        []byte        `protobuf:"bytes,31,opt,name=market_base_vault,json=marketBaseVault,proto3" json:"market_base_vault,omitempty"`
+	BaseDepositTotal       uint64        `protobuf:"varint,32,opt,name=base_deposit_total,json=baseDepositTotal,proto3" json:"base_deposit_total,omitempty"`
+	MarketQuoteVault       []byte        `protobuf:"bytes,33,opt,name=market_quote_vault,json=marketQuoteVault,proto3" json:"market_quote_vault,omitempty"`
+	QuoteDepositTotal      uint64        `protobuf:"varint,34,opt,name=quote_deposit_total,json=quoteDepositTotal,proto3" json:"quote_deposit_total,omitempty"`
+}
+
+func (x *Market) Reset() {
+	*x = Market{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_openbook_v1_openbook_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Market) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Market) ProtoMessage() {}
+
+func (x *Market) ProtoReflect() protoreflect.Message {
+	mi := &file_openbook_v1_openbook_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Market.ProtoReflect.Descriptor instead.
+func (*Market) Descriptor() ([]byte, []int) {
+	return file_openbook_v1_openbook_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Market) GetBump() uint32 {
+	if x != nil {
+		return x.Bump
+	}
+	return 0
+}
+
+func (x *Market) GetBaseDecimals() uint32 {
+	if x != nil {
+		return x.BaseDecimals
+	}
+	return 0
+}
+
+func (x *Market) GetQuoteDecimals() uint32 {
+	if x != nil {
+		return x.QuoteDecimals
+	}
+	return 0
+}
+
+func (x *Market) GetMarketAuthority() []byte {
+	if x != nil {
+		return x.MarketAuthority
+	}
+	return nil
+}
+
+func (x *Market) GetTimeExpiry() int64 {
+	if x != nil {
+		return x.TimeExpiry
+	}
+	return 0
+}
+
+func (x *Market) GetCollectFeeAdmin() []byte {
+	if x != nil {
+		return x.CollectFeeAdmin
+	}
+	return nil
+}
+
+func (x *Market) GetOpenOrdersAdmin() []byte {
+	if x != nil {
+		return x.OpenOrdersAdmin
+	}
+	return nil
+}
+
+func (x *Market) GetConsumeEventsAdmin() []byte {
+	if x != nil {
+		return x.ConsumeEventsAdmin
+	}
+	return nil
+}
+
+func (x *Market) GetCloseMarketAdmin() []byte {
+	if x != nil {
+		return x.CloseMarketAdmin
+	}
+	return nil
+}
+
+func (x *Market) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Market) GetBids() []byte {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *Market) GetAsks() []byte {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *Market) GetEventHeap() []byte {
+	if x != nil {
+		return x.EventHeap
+	}
+	return nil
+}
+
+func (x *Market) GetOracleA() []byte {
+	if x != nil {
+		return x.OracleA
+	}
+	return nil
+}
+
+func (x *Market) GetOracleB() []byte {
+	if x != nil {
+		return x.OracleB
+	}
+	return nil
+}
+
+func (x *Market) GetOracleConfig() *OracleConfig {
+	if x != nil {
+		return x.OracleConfig
+	}
+	return nil
+}
+
+func (x *Market) GetQuoteLotSize() int64 {
+	if x != nil {
+		return x.QuoteLotSize
+	}
+	return 0
+}
+
+func (x *Market) GetBaseLotSize() int64 {
+	if x != nil {
+		return x.BaseLotSize
+	}
+	return 0
+}
+
+func (x *Market) GetSeqNum() uint64 {
+	if x != nil {
+		return x.SeqNum
+	}
+	return 0
+}
+
+func (x *Market) GetRegistrationTime() int64 {
+	if x != nil {
+		return x.RegistrationTime
+	}
+	return 0
+}
+
+func (x *Market) GetMakerFee() int64 {
+	if x != nil {
+		return x.MakerFee
+	}
+	return 0
+}
+
+func (x *Market) GetTakerFee() int64 {
+	if x != nil {
+		return x.TakerFee
+	}
+	return 0
+}
+
+func (x *Market) GetFeesAccrued() *Uint128 {
+	if x != nil {
+		return x.FeesAccrued
+	}
+	return nil
+}
+
+func (x *Market) GetFeesToReferrers() *Uint128 {
+	if x != nil {
+		return x.FeesToReferrers
+	}
+	return nil
+}
+
+func (x *Market) GetReferrerRebatesAccrued() uint64 {
+	if x != nil {
+		return x.ReferrerRebatesAccrued
+	}
+	return 0
+}
+
+func (x *Market) GetFeesAvailable() uint64 {
+	if x != nil {
+		return x.FeesAvailable
+	}
+	return 0
+}
+
+func (x *Market) GetMakerVolume() *Uint128 {
+	if x != nil {
+		return x.MakerVolume
+	}
+	return nil
+}
+
+func (x *Market) GetTakerVolumeWoOo() *Uint128 {
+	if x != nil {
+		return x.TakerVolumeWoOo
+	}
+	return nil
+}
+
+func (x *Market) GetBaseMint() []byte {
+	if x != nil {
+		return x.BaseMint
+	}
+	return nil
+}
+
+func (x *Market) GetQuoteMint() []byte {
+	if x != nil {
+		return x.QuoteMint
+	}
+	return nil
+}
+
+func (x *Market) GetMarketBaseVault() []byte {
+	if x != nil {
+		return x.MarketBaseVault
+	}
+	return nil
+}
+
+func (x *Market) GetBaseDepositTotal() uint64 {
+	if x != nil {
+		return x.BaseDepositTotal
+	}
+	return 0
+}
+
+func (x *Market) GetMarketQuoteVault() []byte {
+	if x != nil {
+		return x.MarketQuoteVault
+	}
+	return nil
+}
+
+func (x *Market) GetQuoteDepositTotal() uint64 {
+	if x != nil {
+		return x.QuoteDepositTotal
+	}
+	return 0
+}
+
+var File_openbook_v1_openbook_proto protoreflect.FileDescriptor
+
+var file_openbook_v1_openbook_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2f, 0x76, 0x31, 0x2f, 0x6f, 0x70,
+	0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6f, 0x70,
+	0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x22, 0x29, 0x0a, 0x07, 0x55, 0x69, 0x6e,
+	0x74, 0x31, 0x32, 0x38, 0x12, 0x0e, 0x0a, 0x02, 0x6c, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x02, 0x6c, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x68, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x02, 0x68, 0x69, 0x22, 0x8a, 0x02, 0x0a, 0x08, 0x4c, 0x65, 0x61, 0x66, 0x4e, 0x6f, 0x64,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x6c, 0x6f, 0x74,
+	0x12, 0x22, 0x0a, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x5f, 0x66, 0x6f, 0x72, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x49, 0x6e, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x55, 0x69, 0x6e, 0x74, 0x31, 0x32, 0x38, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x65, 0x67, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x70, 0x65, 0x67, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0xa2, 0x01, 0x0a, 0x09, 0x49, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x4c, 0x65, 0x6e, 0x12, 0x26,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70,
+	0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x32,
+	0x38, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72,
+	0x65, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72,
+	0x65, 0x6e, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x65, 0x61, 0x72, 0x6c,
+	0x69, 0x65, 0x73, 0x74, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x04, 0x52, 0x13, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x45, 0x61, 0x72, 0x6c, 0x69, 0x65, 0x73, 0x74,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x22, 0x32, 0x0a, 0x08, 0x46, 0x72, 0x65, 0x65, 0x4e, 0x6f,
+	0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x22, 0xb0, 0x01, 0x0a, 0x04, 0x4e,
+	0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x68, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x69,
+	0x6e, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6f, 0x70, 0x65,
+	0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x6e, 0x65, 0x72, 0x4e, 0x6f,
+	0x64, 0x65, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x04, 0x6c,
+	0x65, 0x61, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x70, 0x65, 0x6e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x61, 0x66, 0x4e, 0x6f, 0x64, 0x65,
+	0x48, 0x00, 0x52, 0x04, 0x6c, 0x65, 0x61, 0x66, 0x12, 0x2b, 0x0a, 0x04, 0x66, 0x72, 0x65, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x72, 0x65, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52,
+	0x04, 0x66, 0x72, 0x65, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x22, 0x4d, 0x0a,
+	0x0d, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6d, 0x61, 0x79, 0x62, 0x65, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x6d, 0x61, 0x79, 0x62, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x6c, 0x65, 0x61, 0x66, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x92, 0x02, 0x0a,
+	0x08, 0x42, 0x6f, 0x6f, 0x6b, 0x53, 0x69, 0x64, 0x65, 0x12, 0x42, 0x0a, 0x0f, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x72, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0d,
+	0x6f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x72, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x30, 0x0a,
+	0x05, 0x72, 0x6f, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6f,
+	0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x54, 0x72, 0x65, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x52, 0x05, 0x72, 0x6f, 0x6f, 0x74, 0x73, 0x12,
+	0x1d, 0x0a, 0x0a, 0x62, 0x75, 0x6d, 0x70, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x75, 0x6d, 0x70, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x22,
+	0x0a, 0x0d, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x6c, 0x65, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x66, 0x72, 0x65, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x65, 0x6e, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f,
+	0x68, 0x65, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x66, 0x72, 0x65, 0x65,
+	0x4c, 0x69, 0x73, 0x74, 0x48, 0x65, 0x61, 0x64, 0x12, 0x27, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65,
+	0x73, 0x22, 0x61, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x6f, 0x6b, 0x12, 0x29,
+	0x0a, 0x04, 0x62, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f,
+	0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x53,
+	0x69, 0x64, 0x65, 0x52, 0x04, 0x62, 0x69, 0x64, 0x73, 0x12, 0x29, 0x0a, 0x04, 0x61, 0x73, 0x6b,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x53, 0x69, 0x64, 0x65, 0x52, 0x04,
+	0x61, 0x73, 0x6b, 0x73, 0x22, 0xbd, 0x01, 0x0a, 0x07, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x12, 0x35, 0x0a, 0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x74,
+	0x61, 0x6b, 0x65, 0x6e, 0x5f, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x61, 0x73, 0x65, 0x54, 0x61, 0x6b, 0x65,
+	0x6e, 0x4e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x5f, 0x74, 0x61, 0x6b, 0x65, 0x6e, 0x5f, 0x6e, 0x61, 0x74,
+	0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x51, 0x75, 0x6f, 0x74, 0x65, 0x54, 0x61, 0x6b, 0x65, 0x6e, 0x4e, 0x61, 0x74, 0x69, 0x76, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x66, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x66,
+	0x65, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x6e, 0x6f, 0x74, 0x5f, 0x65, 0x6e, 0x6f, 0x75, 0x67, 0x68,
+	0x5f, 0x6c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x12, 0x6e, 0x6f, 0x74, 0x45, 0x6e, 0x6f, 0x75, 0x67, 0x68, 0x4c, 0x69, 0x71, 0x75, 0x69,
+	0x64, 0x69, 0x74, 0x79, 0x22, 0xc1, 0x02, 0x0a, 0x05, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x30,
+	0x0a, 0x14, 0x6e, 0x6f, 0x74, 0x5f, 0x65, 0x6e, 0x6f, 0x75, 0x67, 0x68, 0x5f, 0x6c, 0x69, 0x71,
+	0x75, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6e, 0x6f,
+	0x74, 0x45, 0x6e, 0x6f, 0x75, 0x67, 0x68, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69, 0x74, 0x79,
+	0x12, 0x27, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x49, 0x6e,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x6d, 0x69, 0x6e,
+	0x5f, 0x6f, 0x75, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x48, 0x01, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x4f, 0x75, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x75, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6f, 0x75, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x65, 0x65, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x65, 0x65, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x66, 0x65, 0x65, 0x4d, 0x69, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x66, 0x65,
+	0x65, 0x5f, 0x70, 0x63, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x65, 0x65,
+	0x50, 0x63, 0x74, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x5f, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x6f, 0x75,
+	0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x5f, 0x0a, 0x0c, 0x4f, 0x72, 0x61, 0x63,
+	0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66,
+	0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63,
+	0x6f, 0x6e, 0x66, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x61, 0x78,
+	0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x6c, 0x65,
+	0x6e, 0x65, 0x73, 0x73, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x22, 0xd3, 0x0a, 0x0a, 0x06, 0x4d, 0x61,
+	0x72, 0x6b, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x75, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x62, 0x75, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65,
+	0x5f, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x62, 0x61, 0x73, 0x65, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x12, 0x25, 0x0a,
+	0x0e, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x5f, 0x64, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x44, 0x65, 0x63, 0x69,
+	0x6d, 0x61, 0x6c, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f,
+	0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12,
+	0x1f, 0x0a, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79,
+	0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x5f, 0x66, 0x65, 0x65, 0x5f,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x63, 0x6f, 0x6c,
+	0x6c, 0x65, 0x63, 0x74, 0x46, 0x65, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x2a, 0x0a, 0x11,
+	0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x6f, 0x70, 0x65, 0x6e, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x73, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x30, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x73,
+	0x75, 0x6d, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x6c,
+	0x6f, 0x73, 0x65, 0x5f, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x4d, 0x61, 0x72,
+	0x6b, 0x65, 0x74, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x69, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x69, 0x64, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x61, 0x73, 0x6b, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x65,
+	0x61, 0x70, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x65, 0x61, 0x70, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x5f, 0x61, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x41, 0x12, 0x19,
+	0x0a, 0x08, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x5f, 0x62, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x6f, 0x72, 0x61, 0x63, 0x6c, 0x65, 0x42, 0x12, 0x3e, 0x0a, 0x0d, 0x6f, 0x72, 0x61,
+	0x63, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4f,
+	0x72, 0x61, 0x63, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0c, 0x6f, 0x72, 0x61,
+	0x63, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x24, 0x0a, 0x0e, 0x71, 0x75, 0x6f,
+	0x74, 0x65, 0x5f, 0x6c, 0x6f, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x4c, 0x6f, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x22, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x6c, 0x6f, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x4c, 0x6f, 0x74, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x75, 0x6d, 0x18, 0x13,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x12, 0x2b, 0x0a, 0x11,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x6b,
+	0x65, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6d, 0x61,
+	0x6b, 0x65, 0x72, 0x46, 0x65, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x5f,
+	0x66, 0x65, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x74, 0x61, 0x6b, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x12, 0x37, 0x0a, 0x0c, 0x66, 0x65, 0x65, 0x73, 0x5f, 0x61, 0x63, 0x63, 0x72,
+	0x75, 0x65, 0x64, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e,
+	0x62, 0x6f, 0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x32, 0x38, 0x52,
+	0x0b, 0x66, 0x65, 0x65, 0x73, 0x41, 0x63, 0x63, 0x72, 0x75, 0x65, 0x64, 0x12, 0x40, 0x0a, 0x11,
+	0x66, 0x65, 0x65, 0x73, 0x5f, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72,
+	0x73, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x32, 0x38, 0x52, 0x0f, 0x66,
+	0x65, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x73, 0x12, 0x38,
+	0x0a, 0x18, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x62, 0x61, 0x74,
+	0x65, 0x73, 0x5f, 0x61, 0x63, 0x63, 0x72, 0x75, 0x65, 0x64, 0x18, 0x19, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x16, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x52, 0x65, 0x62, 0x61, 0x74, 0x65,
+	0x73, 0x41, 0x63, 0x63, 0x72, 0x75, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x65, 0x65, 0x73,
+	0x5f, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0d, 0x66, 0x65, 0x65, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12,
+	0x37, 0x0a, 0x0c, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18,
+	0x1b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x32, 0x38, 0x52, 0x0b, 0x6d, 0x61, 0x6b,
+	0x65, 0x72, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x41, 0x0a, 0x12, 0x74, 0x61, 0x6b, 0x65,
+	0x72, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x5f, 0x77, 0x6f, 0x5f, 0x6f, 0x6f, 0x18, 0x1c,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x32, 0x38, 0x52, 0x0f, 0x74, 0x61, 0x6b, 0x65,
+	0x72, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x57, 0x6f, 0x4f, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x62,
+	0x61, 0x73, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x74, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x4d, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x71, 0x75, 0x6f, 0x74,
+	0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x74, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x71, 0x75,
+	0x6f, 0x74, 0x65, 0x4d, 0x69, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x72, 0x6b, 0x65,
+	0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x1f, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0f, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x42, 0x61, 0x73, 0x65, 0x56, 0x61,
+	0x75, 0x6c, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x20, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x10, 0x62, 0x61, 0x73, 0x65, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x74, 0x61,
+	0x6c, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x71, 0x75, 0x6f, 0x74,
+	0x65, 0x5f, 0x76, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x21, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x6d,
+	0x61, 0x72, 0x6b, 0x65, 0x74, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x56, 0x61, 0x75, 0x6c, 0x74, 0x12,
+	0x2e, 0x0a, 0x13, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x22, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x71, 0x75,
+	0x6f, 0x74, 0x65, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x2a,
+	0x43, 0x0a, 0x0d, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x72, 0x65, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x18, 0x0a, 0x14, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x45, 0x45, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x42, 0x49, 0x44, 0x53, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x4f, 0x52,
+	0x44, 0x45, 0x52, 0x5f, 0x54, 0x52, 0x45, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x41, 0x53,
+	0x4b, 0x53, 0x10, 0x01, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x74, 0x65, 0x78, 0x6f, 0x72, 0x61, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f,
+	0x6f, 0x6b, 0x2d, 0x64, 0x65, 0x78, 0x2d, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x2f, 0x76, 0x31, 0x3b,
+	0x6f, 0x70, 0x65, 0x6e, 0x62, 0x6f, 0x6f, 0x6b, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_openbook_v1_openbook_proto_rawDescOnce sync.Once
+	file_openbook_v1_openbook_proto_rawDescData = file_openbook_v1_openbook_proto_rawDesc
+)
+
+func file_openbook_v1_openbook_proto_rawDescGZIP() []byte {
+	file_openbook_v1_openbook_proto_rawDescOnce.Do(func() {
+		file_openbook_v1_openbook_proto_rawDescData = protoimpl.X.CompressGZIP(file_openbook_v1_openbook_proto_rawDescData)
+	})
+	return file_openbook_v1_openbook_proto_rawDescData
+}
+
+var file_openbook_v1_openbook_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_openbook_v1_openbook_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_openbook_v1_openbook_proto_goTypes = []any{
+	(OrderTreeType)(0),    // 0: openbook.v1.OrderTreeType
+	(*Uint128)(nil),       // 1: openbook.v1.Uint128
+	(*LeafNode)(nil),      // 2: openbook.v1.LeafNode
+	(*InnerNode)(nil),     // 3: openbook.v1.InnerNode
+	(*FreeNode)(nil),      // 4: openbook.v1.FreeNode
+	(*Node)(nil),          // 5: openbook.v1.Node
+	(*OrderTreeRoot)(nil), // 6: openbook.v1.OrderTreeRoot
+	(*BookSide)(nil),      // 7: openbook.v1.BookSide
+	(*Orderbook)(nil),     // 8: openbook.v1.Orderbook
+	(*Amounts)(nil),       // 9: openbook.v1.Amounts
+	(*Quote)(nil),         // 10: openbook.v1.Quote
+	(*OracleConfig)(nil),  // 11: openbook.v1.OracleConfig
+	(*Market)(nil),        // 12: openbook.v1.Market
+}
+var file_openbook_v1_openbook_proto_depIdxs = []int32{
+	1,  // 0: openbook.v1.LeafNode.key:type_name -> openbook.v1.Uint128
+	1,  // 1: openbook.v1.InnerNode.key:type_name -> openbook.v1.Uint128
+	3,  // 2: openbook.v1.Node.inner:type_name -> openbook.v1.InnerNode
+	2,  // 3: openbook.v1.Node.leaf:type_name -> openbook.v1.LeafNode
+	4,  // 4: openbook.v1.Node.free:type_name -> openbook.v1.FreeNode
+	0,  // 5: openbook.v1.BookSide.order_tree_type:type_name -> openbook.v1.OrderTreeType
+	6,  // 6: openbook.v1.BookSide.roots:type_name -> openbook.v1.OrderTreeRoot
+	5,  // 7: openbook.v1.BookSide.nodes:type_name -> openbook.v1.Node
+	7,  // 8: openbook.v1.Orderbook.bids:type_name -> openbook.v1.BookSide
+	7,  // 9: openbook.v1.Orderbook.asks:type_name -> openbook.v1.BookSide
+	11, // 10: openbook.v1.Market.oracle_config:type_name -> openbook.v1.OracleConfig
+	1,  // 11: openbook.v1.Market.fees_accrued:type_name -> openbook.v1.Uint128
+	1,  // 12: openbook.v1.Market.fees_to_referrers:type_name -> openbook.v1.Uint128
+	1,  // 13: openbook.v1.Market.maker_volume:type_name -> openbook.v1.Uint128
+	1,  // 14: openbook.v1.Market.taker_volume_wo_oo:type_name -> openbook.v1.Uint128
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_openbook_v1_openbook_proto_init() }
+func file_openbook_v1_openbook_proto_init() {
+	if File_openbook_v1_openbook_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_openbook_v1_openbook_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Uint128); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*LeafNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*InnerNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FreeNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*Node); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*OrderTreeRoot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*BookSide); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*Orderbook); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Amounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Quote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*OracleConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_openbook_v1_openbook_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Market); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_openbook_v1_openbook_proto_msgTypes[4].OneofWrappers = []any{
+		(*Node_Inner)(nil),
+		(*Node_Leaf)(nil),
+		(*Node_Free)(nil),
+	}
+	file_openbook_v1_openbook_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_openbook_v1_openbook_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_openbook_v1_openbook_proto_goTypes,
+		DependencyIndexes: file_openbook_v1_openbook_proto_depIdxs,
+		EnumInfos:         file_openbook_v1_openbook_proto_enumTypes,
+		MessageInfos:      file_openbook_v1_openbook_proto_msgTypes,
+	}.Build()
+	File_openbook_v1_openbook_proto = out.File
+	file_openbook_v1_openbook_proto_rawDesc = nil
+	file_openbook_v1_openbook_proto_goTypes = nil
+	file_openbook_v1_openbook_proto_depIdxs = nil
+}
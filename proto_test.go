@@ -0,0 +1,188 @@
+package openbookdexgolang
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	openbookv1 "github.com/texora/openbook-dex-golang/proto/openbook/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protoRoundTrip sends the message over the wire format into decoded.
+func protoRoundTrip(t *testing.T, m proto.Message, decoded proto.Message) {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	book := newTestCodecBook(t)
+
+	t.Run("leaf node", func(t *testing.T) {
+		leaf := book.Bids.Nodes.LeafNode(book.Bids.Roots[OraclePeggedOrderTree].MaybeNode)
+		var p openbookv1.LeafNode
+		protoRoundTrip(t, leaf.ToProto(), &p)
+		decoded, err := LeafNodeFromProto(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *decoded != leaf {
+			t.Fatalf("decoded %+v, expected %+v", *decoded, leaf)
+		}
+	})
+
+	t.Run("orderbook", func(t *testing.T) {
+		m, err := book.ToProto()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p openbookv1.Orderbook
+		protoRoundTrip(t, m, &p)
+		decoded, err := OrderbookFromProto(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*decoded.Bids, *book.Bids) || !reflect.DeepEqual(*decoded.Asks, *book.Asks) {
+			t.Fatal("decoded book differs")
+		}
+	})
+
+	t.Run("quote", func(t *testing.T) {
+		quote := newTestCodecQuote(t)
+		var p openbookv1.Quote
+		protoRoundTrip(t, quote.ToProto(), &p)
+		decoded, err := QuoteFromProto(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, quote) {
+			t.Fatalf("decoded %+v, expected %+v", decoded, quote)
+		}
+	})
+
+	t.Run("amounts", func(t *testing.T) {
+		amounts := &Amounts{TotalBaseTakenNative: 1, TotalQuoteTakenNative: 2, Fee: 3, NotEnoughLiquidity: true}
+		var p openbookv1.Amounts
+		protoRoundTrip(t, amounts.ToProto(), &p)
+		if decoded := AmountsFromProto(&p); *decoded != *amounts {
+			t.Fatalf("decoded %+v, expected %+v", *decoded, *amounts)
+		}
+	})
+
+	t.Run("market", func(t *testing.T) {
+		market := newTestCodecMarket()
+		var p openbookv1.Market
+		protoRoundTrip(t, market.ToProto(), &p)
+		decoded, err := MarketFromProto(&p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, market) {
+			t.Fatalf("decoded %+v, expected %+v", decoded, market)
+		}
+	})
+}
+
+func TestProtoRejectsInvalidKeys(t *testing.T) {
+	p := openbookv1.LeafNode{Owner: make([]byte, 31)}
+	if _, err := LeafNodeFromProto(&p); err == nil {
+		t.Fatal("decoded a 31 byte owner")
+	}
+
+	market := newTestCodecMarket().ToProto()
+	market.OracleB = []byte{1}
+	if _, err := MarketFromProto(market); err == nil {
+		t.Fatal("decoded a 1 byte oracle")
+	}
+}
+
+var (
+	protoBlockPattern = regexp.MustCompile(`^(message|enum) (\w+) \{$`)
+	protoFieldPattern = regexp.MustCompile(`^(?:(repeated|optional) )?(\w+) (\w+) = (\d+);$`)
+	protoValuePattern = regexp.MustCompile(`^(\w+) = (\d+);$`)
+)
+
+// protoSourceFields lists the fields and enum values declared in the .proto,
+// as "Message.name label type number".
+func protoSourceFields(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile("proto/openbook/v1/openbook.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make([]string, 0)
+	block := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if match := protoBlockPattern.FindStringSubmatch(line); match != nil {
+			block = match[2]
+		} else if match := protoFieldPattern.FindStringSubmatch(line); match != nil && block != "" {
+			fields = append(fields, fmt.Sprintf("%s.%s %s %s %s", block, match[3], match[1], match[2], match[4]))
+		} else if match := protoValuePattern.FindStringSubmatch(line); match != nil && block != "" {
+			fields = append(fields, fmt.Sprintf("%s.%s %s", block, match[1], match[2]))
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// protoGeneratedFields lists the same as protoSourceFields from the
+// descriptor compiled into openbook.pb.go.
+func protoGeneratedFields() []string {
+	file := openbookv1.File_openbook_v1_openbook_proto
+	fields := make([]string, 0)
+	for i := 0; i < file.Messages().Len(); i++ {
+		message := file.Messages().Get(i)
+		for j := 0; j < message.Fields().Len(); j++ {
+			field := message.Fields().Get(j)
+			label := ""
+			if field.Cardinality() == protoreflect.Repeated {
+				label = "repeated"
+			} else if field.HasOptionalKeyword() {
+				label = "optional"
+			}
+			kind := field.Kind().String()
+			switch field.Kind() {
+			case protoreflect.MessageKind:
+				kind = string(field.Message().Name())
+			case protoreflect.EnumKind:
+				kind = string(field.Enum().Name())
+			}
+			fields = append(fields, fmt.Sprintf("%s.%s %s %s %d", message.Name(), field.Name(), label, kind, field.Number()))
+		}
+	}
+	for i := 0; i < file.Enums().Len(); i++ {
+		enum := file.Enums().Get(i)
+		for j := 0; j < enum.Values().Len(); j++ {
+			value := enum.Values().Get(j)
+			fields = append(fields, fmt.Sprintf("%s.%s %d", enum.Name(), value.Name(), value.Number()))
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// TestGeneratedProtoIsCurrent fails when openbook.proto was changed without
+// running go generate.
+func TestGeneratedProtoIsCurrent(t *testing.T) {
+	source := protoSourceFields(t)
+	generated := protoGeneratedFields()
+	if len(source) == 0 {
+		t.Fatal("no fields found in openbook.proto")
+	}
+	if !reflect.DeepEqual(source, generated) {
+		t.Fatalf("openbook.pb.go is out of date, run go generate\n.proto: %v\n.pb.go: %v", source, generated)
+	}
+}
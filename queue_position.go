@@ -0,0 +1,96 @@
+package openbookdexgolang
+
+import (
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// QueuePosition is where a resting order stands on its side of the book.
+// Only valid orders are counted ahead, takers drop the others.
+type QueuePosition struct {
+	Handle    BookSideOrderHandle
+	PriceLots int64
+	BaseLots  int64 // Size of the order itself
+
+	// Orders at the same price matched first, by their lower sequence number
+	OrdersAheadAtPrice   int
+	BaseLotsAheadAtPrice int64
+
+	// Orders at better prices
+	OrdersAheadBetter    int
+	BaseLotsAheadBetter  int64
+	QuoteLotsAheadBetter int64
+
+	// Taker size needed to fill the order completely
+	BaseLotsToFill  int64
+	QuoteLotsToFill int64
+}
+
+// QueuePosition returns the position of the order with the given id.
+func (b *BookSide) QueuePosition(id OrderId, nowTs uint64, oraclePriceLots *int64) (*QueuePosition, error) {
+	return b.queuePosition(nowTs, oraclePriceLots, func(leaf *LeafNode) bool {
+		return leaf.OrderId() == id
+	})
+}
+
+// QueuePositionByOwner returns the position of the order in the given slot of
+// an OpenOrders account.
+func (b *BookSide) QueuePositionByOwner(openOrdersAccount solana.PublicKey, ownerSlot uint8, nowTs uint64, oraclePriceLots *int64) (*QueuePosition, error) {
+	return b.queuePosition(nowTs, oraclePriceLots, func(leaf *LeafNode) bool {
+		return leaf.Owner == openOrdersAccount && leaf.OwnerSlot == ownerSlot
+	})
+}
+
+// queuePosition walks the side in matching order, adding up the valid orders
+// until the one matching is reached.
+func (b *BookSide) queuePosition(nowTs uint64, oraclePriceLots *int64, match func(leaf *LeafNode) bool) (*QueuePosition, error) {
+	var position QueuePosition
+
+	// Valid orders at the last price seen, not yet known to be better
+	var levelPriceLots, levelBaseLots int64
+	var levelOrders int
+	iter := b.IterAllIncludingInvalid(nowTs, oraclePriceLots)
+	for item, ok := iter.Next(); ok; item, ok = iter.Next() {
+		if match(&item.Node) {
+			if !item.IsValid() {
+				return nil, errors.New("order is expired or beyond its peg limit")
+			}
+
+			if levelOrders > 0 && levelPriceLots != item.PriceLots {
+				position.addBetter(levelPriceLots, levelOrders, levelBaseLots)
+				levelOrders, levelBaseLots = 0, 0
+			}
+			position.Handle = item.Handle
+			position.PriceLots = item.PriceLots
+			position.BaseLots = item.Node.Quantity
+			position.OrdersAheadAtPrice = levelOrders
+			position.BaseLotsAheadAtPrice = levelBaseLots
+
+			// Orders ahead at the same price are matched at it as well
+			position.BaseLotsToFill = position.BaseLotsAheadBetter + levelBaseLots + item.Node.Quantity
+			position.QuoteLotsToFill = saturatingAdd(position.QuoteLotsAheadBetter, (levelBaseLots+item.Node.Quantity)*item.PriceLots)
+			return &position, nil
+		}
+
+		if !item.IsValid() {
+			continue
+		}
+
+		if levelOrders > 0 && levelPriceLots != item.PriceLots {
+			position.addBetter(levelPriceLots, levelOrders, levelBaseLots)
+			levelOrders, levelBaseLots = 0, 0
+		}
+		levelPriceLots = item.PriceLots
+		levelOrders++
+		levelBaseLots += item.Node.Quantity
+	}
+
+	return nil, errors.New("order not found")
+}
+
+func (p *QueuePosition) addBetter(priceLots int64, orders int, baseLots int64) {
+	p.OrdersAheadBetter += orders
+	p.BaseLotsAheadBetter += baseLots
+	p.QuoteLotsAheadBetter = saturatingAdd(p.QuoteLotsAheadBetter, baseLots*priceLots)
+}
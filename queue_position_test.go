@@ -0,0 +1,169 @@
+package openbookdexgolang
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// slotted returns the order in the given slot of the test owner.
+func slotted(order BookOrder, ownerSlot uint8) BookOrder {
+	order.OwnerSlot = ownerSlot
+	return order
+}
+
+func newQueueTestBids(t *testing.T, orders ...BookOrder) *BookSide {
+	t.Helper()
+	book, err := BuildOrderbook(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return book.Bids
+}
+
+// checkQueuePosition compares the position with the expected one, ignoring
+// its handle.
+func checkQueuePosition(t *testing.T, position *QueuePosition, expected QueuePosition) {
+	t.Helper()
+	expected.Handle = position.Handle
+	if *position != expected {
+		t.Fatalf("position %+v, expected %+v", *position, expected)
+	}
+}
+
+func TestQueuePositionAtPrice(t *testing.T) {
+	bids := newQueueTestBids(t, bid(100, 5), bid(101, 3), bid(100, 7), bid(100, 4), bid(99, 2))
+
+	// The fourth bid, behind two at its price and one better
+	id, err := NewFixedOrderId(Bid, 100, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	position, err := bids.QueuePosition(id, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQueuePosition(t, position, QueuePosition{
+		PriceLots:            100,
+		BaseLots:             4,
+		OrdersAheadAtPrice:   2,
+		BaseLotsAheadAtPrice: 12,
+		OrdersAheadBetter:    1,
+		BaseLotsAheadBetter:  3,
+		QuoteLotsAheadBetter: 303,
+		BaseLotsToFill:       19,
+		QuoteLotsToFill:      303 + 16*100,
+	})
+}
+
+func TestQueuePositionSkipsExpiredOrders(t *testing.T) {
+	expiredBetter := slotted(bid(101, 3), 0)
+	expiredBetter.TimeInForce = 10
+	expiredAtPrice := slotted(bid(100, 5), 1)
+	expiredAtPrice.TimeInForce = 10
+	bids := newQueueTestBids(t, expiredBetter, expiredAtPrice, slotted(bid(100, 2), 2))
+
+	position, err := bids.QueuePositionByOwner(testOwner, 2, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQueuePosition(t, position, QueuePosition{PriceLots: 100, BaseLots: 2, BaseLotsToFill: 2, QuoteLotsToFill: 200})
+
+	// Before they expire they are ahead
+	position, err = bids.QueuePositionByOwner(testOwner, 2, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position.OrdersAheadBetter != 1 || position.OrdersAheadAtPrice != 1 || position.BaseLotsToFill != 10 {
+		t.Fatalf("position %+v, expected one order ahead at the price and one better", *position)
+	}
+}
+
+func TestQueuePositionPeggedOrdersAhead(t *testing.T) {
+	pegged := func(offsetLots, pegLimit, quantity int64, ownerSlot uint8) BookOrder {
+		return BookOrder{Side: Bid, OraclePegged: true, PriceLots: offsetLots, PegLimit: pegLimit, Quantity: quantity, Owner: testOwner, OwnerSlot: ownerSlot}
+	}
+	bids := newQueueTestBids(t,
+		pegged(1, -1, 3, 0),
+		pegged(0, -1, 4, 1),
+		pegged(5, 102, 8, 2), // Beyond its peg limit at the oracle price
+		slotted(bid(100, 6), 3),
+	)
+
+	// At an oracle price of 100 the pegged bids are at 101, 100 and 105
+	oraclePriceLots := int64(100)
+	position, err := bids.QueuePositionByOwner(testOwner, 3, 100, &oraclePriceLots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQueuePosition(t, position, QueuePosition{
+		PriceLots:            100,
+		BaseLots:             6,
+		OrdersAheadAtPrice:   1,
+		BaseLotsAheadAtPrice: 4,
+		OrdersAheadBetter:    1,
+		BaseLotsAheadBetter:  3,
+		QuoteLotsAheadBetter: 303,
+		BaseLotsToFill:       13,
+		QuoteLotsToFill:      303 + 10*100,
+	})
+
+	// Without an oracle price pegged orders are ignored
+	position, err = bids.QueuePositionByOwner(testOwner, 3, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkQueuePosition(t, position, QueuePosition{PriceLots: 100, BaseLots: 6, BaseLotsToFill: 6, QuoteLotsToFill: 600})
+}
+
+func TestQueuePositionByOwner(t *testing.T) {
+	other := solana.PublicKey{10}
+	otherBid := bid(102, 9)
+	otherBid.Owner = other
+	bids := newQueueTestBids(t, slotted(bid(100, 5), 0), otherBid, slotted(bid(101, 3), 1))
+
+	for _, test := range []struct {
+		owner     solana.PublicKey
+		ownerSlot uint8
+		priceLots int64
+		ahead     int
+	}{
+		{testOwner, 0, 100, 2},
+		{testOwner, 1, 101, 1},
+		{other, 0, 102, 0},
+	} {
+		position, err := bids.QueuePositionByOwner(test.owner, test.ownerSlot, 100, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if position.PriceLots != test.priceLots || position.OrdersAheadBetter != test.ahead {
+			t.Fatalf("slot %d of %s: position %+v, expected at %d with %d ahead", test.ownerSlot, test.owner, *position, test.priceLots, test.ahead)
+		}
+	}
+
+	if _, err := bids.QueuePositionByOwner(other, 1, 100, nil); err == nil {
+		t.Fatal("found an order in an empty slot")
+	}
+}
+
+func TestQueuePositionInvalidTarget(t *testing.T) {
+	expired := slotted(bid(100, 5), 0)
+	expired.TimeInForce = 10
+	beyondPegLimit := BookOrder{Side: Bid, OraclePegged: true, PriceLots: 5, PegLimit: 102, Quantity: 8, Owner: testOwner, OwnerSlot: 1}
+	bids := newQueueTestBids(t, expired, beyondPegLimit)
+
+	oraclePriceLots := int64(100)
+	for _, ownerSlot := range []uint8{0, 1} {
+		if _, err := bids.QueuePositionByOwner(testOwner, ownerSlot, 100, &oraclePriceLots); err == nil {
+			t.Fatalf("position of the invalid order in slot %d", ownerSlot)
+		}
+	}
+
+	missing, err := NewFixedOrderId(Bid, 100, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bids.QueuePosition(missing, 100, &oraclePriceLots); err == nil {
+		t.Fatal("position of a missing order")
+	}
+}
@@ -0,0 +1,137 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// MarketRegistry holds an immutable snapshot of every registered market.
+//
+// Readers load the current snapshots without locking and can quote from them
+// concurrently. Writers never modify a published OpenBookMarket: updates are
+// applied to a copy which then atomically replaces the previous snapshot.
+type MarketRegistry struct {
+	// Serializes writers, readers only use markets
+	mu      sync.Mutex
+	markets atomic.Pointer[map[solana.PublicKey]*OpenBookMarket]
+}
+
+func NewMarketRegistry() *MarketRegistry {
+	r := &MarketRegistry{}
+	markets := make(map[solana.PublicKey]*OpenBookMarket)
+	r.markets.Store(&markets)
+	return r
+}
+
+// Publish makes obm the current snapshot of its market. obm must not be
+// modified afterwards.
+func (r *MarketRegistry) Publish(obm *OpenBookMarket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(obm.key, obm)
+}
+
+// Remove drops the market from the registry.
+func (r *MarketRegistry) Remove(key solana.PublicKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(key, nil)
+}
+
+// store swaps in a copy of the market map with key set to obm, or removed when
+// obm is nil. Callers must hold mu.
+func (r *MarketRegistry) store(key solana.PublicKey, obm *OpenBookMarket) {
+	current := *r.markets.Load()
+	next := make(map[solana.PublicKey]*OpenBookMarket, len(current)+1)
+	for k, v := range current {
+		next[k] = v
+	}
+	if obm == nil {
+		delete(next, key)
+	} else {
+		next[key] = obm
+	}
+	r.markets.Store(&next)
+}
+
+// Modify applies f to a copy of the market's current snapshot and publishes
+// the copy, unless f fails. The previous snapshot stays valid for readers still
+// using it, and f must not keep the copy.
+func (r *MarketRegistry) Modify(key solana.PublicKey, f func(next *OpenBookMarket) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := (*r.markets.Load())[key]
+	if !ok {
+		return errors.New("market not registered")
+	}
+
+	next := *current
+	if err := f(&next); err != nil {
+		return err
+	}
+	r.store(key, &next)
+	return nil
+}
+
+// Update applies the account data to a copy of the market's current snapshot
+// and publishes it.
+func (r *MarketRegistry) Update(key solana.PublicKey, accounts map[solana.PublicKey][]byte) error {
+	return r.Modify(key, func(next *OpenBookMarket) error {
+		return next.Update(accounts)
+	})
+}
+
+// UpdateAtSlot is Update with OpenBookMarket.UpdateAtSlot, the snapshot is
+// kept when the accounts are rejected.
+func (r *MarketRegistry) UpdateAtSlot(key solana.PublicKey, accounts *SlotAccounts) error {
+	return r.Modify(key, func(next *OpenBookMarket) error {
+		return next.UpdateAtSlot(accounts)
+	})
+}
+
+// SetOraclePriceLots publishes a copy of the market's snapshot quoting pegged
+// orders at the given oracle price, see OpenBookMarket.SetOraclePriceLots.
+func (r *MarketRegistry) SetOraclePriceLots(key solana.PublicKey, priceLots *int64) error {
+	return r.Modify(key, func(next *OpenBookMarket) error {
+		next.SetOraclePriceLots(priceLots)
+		return nil
+	})
+}
+
+// OverrideTimestamp publishes a copy of the market's snapshot with the
+// timestamp pinned or unpinned, see OpenBookMarket.OverrideTimestamp.
+func (r *MarketRegistry) OverrideTimestamp(key solana.PublicKey, timestamp *uint64) error {
+	return r.Modify(key, func(next *OpenBookMarket) error {
+		next.OverrideTimestamp(timestamp)
+		return nil
+	})
+}
+
+// Get returns the current snapshot of the market, or nil when it is not
+// registered. The snapshot must not be modified.
+func (r *MarketRegistry) Get(key solana.PublicKey) *OpenBookMarket {
+	return (*r.markets.Load())[key]
+}
+
+// Markets returns the current snapshot of every market.
+func (r *MarketRegistry) Markets() []*OpenBookMarket {
+	markets := *r.markets.Load()
+	snapshots := make([]*OpenBookMarket, 0, len(markets))
+	for _, obm := range markets {
+		snapshots = append(snapshots, obm)
+	}
+	return snapshots
+}
+
+// Quote quotes against the current snapshot of the market.
+func (r *MarketRegistry) Quote(key solana.PublicKey, quoteParams *QuoteParams) (*Quote, error) {
+	obm := r.Get(key)
+	if obm == nil {
+		return nil, errors.New("market not registered")
+	}
+	return obm.Quote(quoteParams)
+}
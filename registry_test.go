@@ -0,0 +1,233 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// newRegistryTestMarket registers a market over the accounts of
+// newSlotTestMarket and returns them with a second book, whose asks add an
+// oracle pegged order of 3 lots at the oracle price.
+func newRegistryTestMarket(t *testing.T) (*MarketRegistry, *OpenBookMarket, map[solana.PublicKey][]byte, map[solana.PublicKey][]byte) {
+	t.Helper()
+	accounts := make(map[solana.PublicKey][]byte)
+	obm := newSlotTestMarket(t, 1, accounts)
+
+	pegged := make(map[solana.PublicKey][]byte, len(accounts))
+	for key, data := range accounts {
+		pegged[key] = data
+	}
+	book, err := BuildOrderbook([]BookOrder{
+		bid(99, 1),
+		ask(101, 1),
+		{Side: Ask, OraclePegged: true, PegLimit: -1, Quantity: 3, Owner: testOwner},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pegged[obm.market.Asks] = encodeTestAccount(t, bookSideDiscriminator, book.Asks)
+
+	registry := NewMarketRegistry()
+	registry.Publish(obm)
+	if err := registry.Update(obm.key, accounts); err != nil {
+		t.Fatal(err)
+	}
+	return registry, obm, accounts, pegged
+}
+
+func registryTestQuote(t *testing.T, registry *MarketRegistry, key solana.PublicKey) *Quote {
+	t.Helper()
+	quote, err := registry.Quote(key, &QuoteParams{InAmount: 1_000, InputMint: testQuoteMint, OutputMint: testBaseMint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return quote
+}
+
+func TestRegistrySetOraclePriceLots(t *testing.T) {
+	registry, obm, _, pegged := newRegistryTestMarket(t)
+	if err := registry.Update(obm.key, pegged); err != nil {
+		t.Fatal(err)
+	}
+	before := registry.Get(obm.key)
+
+	priceLots := int64(100)
+	if err := registry.SetOraclePriceLots(obm.key, &priceLots); err != nil {
+		t.Fatal(err)
+	}
+	priceLots = 1
+
+	after := registry.Get(obm.key)
+	if after == before {
+		t.Fatal("the published snapshot was modified in place")
+	}
+	if before.OraclePriceLots() != nil {
+		t.Fatalf("previous snapshot has oracle price %d", *before.OraclePriceLots())
+	}
+	if after.OraclePriceLots() == nil || *after.OraclePriceLots() != 100 {
+		t.Fatalf("oracle price %v, expected 100", after.OraclePriceLots())
+	}
+	if quote := registryTestQuote(t, registry, obm.key); quote.OutAmount != 4 {
+		t.Fatalf("%d out with the pegged order at 100, expected 4", quote.OutAmount)
+	}
+
+	// The price is kept over account updates
+	if err := registry.Update(obm.key, pegged); err != nil {
+		t.Fatal(err)
+	}
+	if quote := registryTestQuote(t, registry, obm.key); quote.OutAmount != 4 {
+		t.Fatalf("%d out after an update, expected 4", quote.OutAmount)
+	}
+
+	if err := registry.SetOraclePriceLots(obm.key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if quote := registryTestQuote(t, registry, obm.key); quote.OutAmount != 1 {
+		t.Fatalf("%d out without an oracle price, expected 1", quote.OutAmount)
+	}
+}
+
+func TestRegistryOverrideTimestamp(t *testing.T) {
+	registry, obm, accounts, _ := newRegistryTestMarket(t)
+	before := registry.Get(obm.key)
+
+	timestamp := uint64(1_000)
+	if err := registry.OverrideTimestamp(obm.key, &timestamp); err != nil {
+		t.Fatal(err)
+	}
+	if before.Timestamp() != 1 || registry.Get(obm.key).Timestamp() != 1_000 {
+		t.Fatalf("timestamps %d and %d, expected 1 and 1000", before.Timestamp(), registry.Get(obm.key).Timestamp())
+	}
+
+	// Pinned timestamps survive updates until unpinned
+	if err := registry.Update(obm.key, accounts); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Get(obm.key).Timestamp() != 1_000 {
+		t.Fatalf("timestamp %d after an update, expected 1000", registry.Get(obm.key).Timestamp())
+	}
+	if err := registry.OverrideTimestamp(obm.key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Update(obm.key, accounts); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Get(obm.key).Timestamp() != 1 {
+		t.Fatalf("timestamp %d after unpinning, expected the clock's 1", registry.Get(obm.key).Timestamp())
+	}
+}
+
+func TestRegistryUnknownMarket(t *testing.T) {
+	registry := NewMarketRegistry()
+	key := solana.PublicKey{9}
+	priceLots := int64(1)
+	timestamp := uint64(1)
+	for name, err := range map[string]error{
+		"Update":             registry.Update(key, nil),
+		"UpdateAtSlot":       registry.UpdateAtSlot(key, &SlotAccounts{}),
+		"SetOraclePriceLots": registry.SetOraclePriceLots(key, &priceLots),
+		"OverrideTimestamp":  registry.OverrideTimestamp(key, &timestamp),
+	} {
+		if err == nil {
+			t.Errorf("%s of an unknown market succeeded", name)
+		}
+	}
+	if _, err := registry.Quote(key, &QuoteParams{}); err == nil {
+		t.Error("quoted an unknown market")
+	}
+}
+
+func TestRegistryKeepsSnapshotOnFailedUpdate(t *testing.T) {
+	registry, obm, accounts, _ := newRegistryTestMarket(t)
+	before := registry.Get(obm.key)
+
+	broken := make(map[solana.PublicKey][]byte, len(accounts))
+	for key, data := range accounts {
+		broken[key] = data
+	}
+	broken[obm.market.Asks] = []byte{1, 2, 3}
+	if err := registry.Update(obm.key, broken); err == nil {
+		t.Fatal("update with a corrupt asks account succeeded")
+	}
+	if registry.Get(obm.key) != before {
+		t.Fatal("a failed update replaced the snapshot")
+	}
+}
+
+// TestRegistryConcurrentQuotes quotes and reads books while the market is
+// updated, re-priced and re-published. Run with -race.
+func TestRegistryConcurrentQuotes(t *testing.T) {
+	registry, obm, accounts, pegged := newRegistryTestMarket(t)
+	clock := bytes.NewBuffer(nil)
+	if err := bin.NewBinEncoder(clock).Encode(&Clock{UnixTimestamp: 2}); err != nil {
+		t.Fatal(err)
+	}
+	pegged[solana.SysVarClockPubkey] = clock.Bytes()
+
+	// An empty book, a book without the pegged order, or with it at 100
+	allowed := map[uint64]bool{0: true, 1: true, 4: true}
+
+	const readers = 4
+	const writes = 200
+	done := make(chan struct{})
+	errs := make(chan string, readers)
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				quote, err := registry.Quote(obm.key, &QuoteParams{InAmount: 1_000, InputMint: testQuoteMint, OutputMint: testBaseMint})
+				if err != nil {
+					errs <- err.Error()
+					return
+				}
+				if !allowed[quote.OutAmount] {
+					errs <- "quoted a half updated snapshot"
+					return
+				}
+				snapshot := registry.Get(obm.key)
+				book := snapshot.Orderbook()
+				book.Asks.L2(snapshot.Timestamp(), snapshot.OraclePriceLots(), 10)
+				registry.Markets()
+			}
+		}()
+	}
+
+	priceLots := int64(100)
+	for i := 0; i < writes; i++ {
+		var err error
+		switch i % 5 {
+		case 0:
+			err = registry.Update(obm.key, accounts)
+		case 1:
+			err = registry.Update(obm.key, pegged)
+		case 2:
+			err = registry.SetOraclePriceLots(obm.key, &priceLots)
+		case 3:
+			timestamp := uint64(i)
+			err = registry.OverrideTimestamp(obm.key, &timestamp)
+		case 4:
+			registry.Publish(NewOpenBookMarket(obm.key, &obm.market))
+		}
+		if err != nil {
+			t.Error(err)
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,161 @@
+package openbookdexgolang
+
+import (
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// RouteLeg is one market traded through on a route.
+type RouteLeg struct {
+	Market     solana.PublicKey
+	InputMint  solana.PublicKey
+	OutputMint solana.PublicKey
+	Quote      *Quote
+}
+
+// Route is a swap through one or two markets. InAmount is the requested input
+// of the first leg, OutAmount is given by the last one.
+type Route struct {
+	Legs               []RouteLeg
+	InAmount           uint64
+	UnfilledInAmount   uint64 // Part of InAmount the first leg couldn't take
+	OutAmount          uint64
+	NotEnoughLiquidity bool // Set when any leg couldn't take its whole input
+}
+
+// betterThan ranks routes taking their whole input above those that don't,
+// then by out amount.
+func (route *Route) betterThan(other *Route) bool {
+	if route.NotEnoughLiquidity != other.NotEnoughLiquidity {
+		return !route.NotEnoughLiquidity
+	}
+	if route.OutAmount != other.OutAmount {
+		return route.OutAmount > other.OutAmount
+	}
+	return route.UnfilledInAmount < other.UnfilledInAmount
+}
+
+// routeEdge trades from the mint it is listed under to outputMint.
+type routeEdge struct {
+	market     *OpenBookMarket
+	outputMint solana.PublicKey
+}
+
+// Router finds the best route between two mints over a fixed set of markets.
+//
+// Every market is an edge in both directions between its reserve mints. The
+// markets are only read, so a Router can be built from registry snapshots.
+type Router struct {
+	edges map[solana.PublicKey][]routeEdge
+}
+
+func NewRouter(markets []*OpenBookMarket) *Router {
+	edges := make(map[solana.PublicKey][]routeEdge)
+	for _, obm := range markets {
+		mints := obm.ReserveMints()
+		edges[mints[0]] = append(edges[mints[0]], routeEdge{market: obm, outputMint: mints[1]})
+		edges[mints[1]] = append(edges[mints[1]], routeEdge{market: obm, outputMint: mints[0]})
+	}
+	return &Router{edges: edges}
+}
+
+// BestRoute returns the one or two hop route giving the most outputMint for
+// inAmount of inputMint. The second leg of a two hop route is quoted with the
+// out amount of the first one.
+//
+// A route whose legs take their whole input always beats one that leaves part
+// of it unfilled, whatever their out amounts. Only when no route can take the
+// whole input is the best partial one returned, marked NotEnoughLiquidity.
+func (r *Router) BestRoute(inputMint, outputMint solana.PublicKey, inAmount uint64) (*Route, error) {
+	if inputMint == outputMint {
+		return nil, errors.New("input and output mint are the same")
+	}
+
+	var best *Route
+	var quoteErr error
+	consider := func(legs ...RouteLeg) {
+		route := &Route{
+			Legs:      legs,
+			InAmount:  inAmount,
+			OutAmount: legs[len(legs)-1].Quote.OutAmount,
+		}
+		if taken := legs[0].Quote.InAmount; taken < inAmount {
+			route.UnfilledInAmount = inAmount - taken
+		}
+		for _, leg := range legs {
+			route.NotEnoughLiquidity = route.NotEnoughLiquidity || leg.Quote.NotEnoughLiquidity
+		}
+		if best == nil || route.betterThan(best) {
+			best = route
+		}
+	}
+
+	for _, first := range r.edges[inputMint] {
+		firstLeg, err := quoteLeg(first, inputMint, inAmount)
+		if err != nil {
+			quoteErr = err
+			continue
+		}
+		if firstLeg == nil {
+			continue
+		}
+
+		// Direct route
+		if first.outputMint == outputMint {
+			consider(*firstLeg)
+			continue
+		}
+
+		// Two hops through the first leg's output mint
+		for _, second := range r.edges[first.outputMint] {
+			if second.outputMint != outputMint || second.market == first.market {
+				continue
+			}
+			secondLeg, err := quoteLeg(second, first.outputMint, firstLeg.Quote.OutAmount)
+			if err != nil {
+				quoteErr = err
+				continue
+			}
+			if secondLeg == nil {
+				continue
+			}
+			consider(*firstLeg, *secondLeg)
+		}
+	}
+
+	if best == nil {
+		if quoteErr != nil {
+			return nil, quoteErr
+		}
+		return nil, errors.New("no route found")
+	}
+	return best, nil
+}
+
+// quoteLeg quotes inAmount of inputMint over the edge. It returns nil when
+// nothing can be filled.
+func quoteLeg(edge routeEdge, inputMint solana.PublicKey, inAmount uint64) (*RouteLeg, error) {
+	if inAmount == 0 {
+		return nil, nil
+	}
+
+	quote, err := edge.market.Quote(&QuoteParams{
+		InAmount:   inAmount,
+		InputMint:  inputMint,
+		OutputMint: edge.outputMint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if quote.OutAmount == 0 {
+		return nil, nil
+	}
+
+	return &RouteLeg{
+		Market:     edge.market.Key(),
+		InputMint:  inputMint,
+		OutputMint: edge.outputMint,
+		Quote:      quote,
+	}, nil
+}
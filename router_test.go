@@ -0,0 +1,121 @@
+package openbookdexgolang
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var testOtherMint = solana.PublicKey{20}
+
+// newRouterTestMarket returns a market between the given mints at key {n}.
+func newRouterTestMarket(t *testing.T, n byte, baseMint, quoteMint solana.PublicKey, orders ...BookOrder) *OpenBookMarket {
+	t.Helper()
+	market := newTestMarketAccount()
+	market.BaseMint = baseMint
+	market.QuoteMint = quoteMint
+	obm := newTestMarket(t, market, orders...)
+	obm.key = solana.PublicKey{n}
+	return obm
+}
+
+func routeMarkets(route *Route) []solana.PublicKey {
+	markets := make([]solana.PublicKey, 0, len(route.Legs))
+	for _, leg := range route.Legs {
+		markets = append(markets, leg.Market)
+	}
+	return markets
+}
+
+func TestBestRoutePrefersFilledRoutes(t *testing.T) {
+	// Buying base with quote: directly, or through the other mint
+	direct := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(50, 6))
+	first := newRouterTestMarket(t, 2, testOtherMint, testQuoteMint, ask(10, 100))
+	second := newRouterTestMarket(t, 3, testBaseMint, testOtherMint, ask(20, 10))
+	router := NewRouter([]*OpenBookMarket{direct, first, second})
+
+	// The direct market gives 6 base for 300 of the 1000 quote, the two hops
+	// give 5 base for all of it
+	route, err := router.BestRoute(testQuoteMint, testBaseMint, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	markets := routeMarkets(route)
+	if len(markets) != 2 || markets[0] != first.key || markets[1] != second.key {
+		t.Fatalf("route through %v, expected the two hops", markets)
+	}
+	if route.InAmount != 1_000 || route.UnfilledInAmount != 0 || route.OutAmount != 5 || route.NotEnoughLiquidity {
+		t.Fatalf("route %+v, expected 1000 in and 5 out", route)
+	}
+
+	// Once the direct market can fill it all, its better price wins
+	direct = newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(50, 100))
+	router = NewRouter([]*OpenBookMarket{direct, first, second})
+	route, err = router.BestRoute(testQuoteMint, testBaseMint, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if markets := routeMarkets(route); len(markets) != 1 || markets[0] != direct.key || route.OutAmount != 20 {
+		t.Fatalf("route through %v for %d out, expected 20 directly", markets, route.OutAmount)
+	}
+}
+
+func TestBestRoutePartialFill(t *testing.T) {
+	direct := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(50, 6))
+	first := newRouterTestMarket(t, 2, testOtherMint, testQuoteMint, ask(10, 10))
+	second := newRouterTestMarket(t, 3, testBaseMint, testOtherMint, ask(20, 10))
+	router := NewRouter([]*OpenBookMarket{direct, first, second})
+
+	// Neither fills, the most out of the partial routes is returned
+	route, err := router.BestRoute(testQuoteMint, testBaseMint, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if markets := routeMarkets(route); len(markets) != 1 || markets[0] != direct.key {
+		t.Fatalf("route through %v, expected the direct market", markets)
+	}
+	if route.InAmount != 1_000 || route.UnfilledInAmount != 700 || route.OutAmount != 6 || !route.NotEnoughLiquidity {
+		t.Fatalf("route %+v, expected 700 of 1000 unfilled for 6 out", route)
+	}
+}
+
+func TestBestRouteSkipsPermissionedMarkets(t *testing.T) {
+	permissionedAccount := newTestMarketAccount()
+	permissionedAccount.OpenOrdersAdmin = NonZeroPubkeyOption{Key: solana.PublicKey{9}}
+	permissioned := newTestMarket(t, permissionedAccount, ask(1, 1_000))
+	permissioned.key = solana.PublicKey{1}
+	open := newRouterTestMarket(t, 2, testBaseMint, testQuoteMint, ask(100, 100))
+
+	route, err := NewRouter([]*OpenBookMarket{permissioned, open}).BestRoute(testQuoteMint, testBaseMint, 1_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if markets := routeMarkets(route); len(markets) != 1 || markets[0] != open.key || route.OutAmount != 10 {
+		t.Fatalf("route through %v for %d out, expected 10 through the open market", markets, route.OutAmount)
+	}
+
+	if _, err := NewRouter([]*OpenBookMarket{permissioned}).BestRoute(testQuoteMint, testBaseMint, 1_000); err == nil {
+		t.Fatal("routed through a permissioned market")
+	}
+}
+
+func TestBestRouteNoRoute(t *testing.T) {
+	market := newRouterTestMarket(t, 1, testBaseMint, testQuoteMint, ask(100, 1))
+	router := NewRouter([]*OpenBookMarket{market})
+
+	cases := []struct {
+		name                  string
+		inputMint, outputMint solana.PublicKey
+		inAmount              uint64
+	}{
+		{"unknown mint", testQuoteMint, testOtherMint, 100},
+		{"same mint", testQuoteMint, testQuoteMint, 100},
+		{"empty side", testBaseMint, testQuoteMint, 100},
+		{"nothing fillable", testQuoteMint, testBaseMint, 99},
+	}
+	for _, c := range cases {
+		if route, err := router.BestRoute(c.inputMint, c.outputMint, c.inAmount); err == nil {
+			t.Errorf("%s: route %+v", c.name, route)
+		}
+	}
+}
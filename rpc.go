@@ -0,0 +1,175 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// RPCClient is the part of the Solana JSON-RPC API used to discover and load
+// accounts.
+type RPCClient interface {
+	// GetProgramAccounts returns the accounts owned by program matching every
+	// filter.
+	GetProgramAccounts(ctx context.Context, program solana.PublicKey, filters []RPCFilter) ([]ProgramAccount, error)
+
+	// GetMultipleAccounts returns the data of the accounts in the order of
+	// keys, nil for the ones that don't exist, and the slot they were read at.
+	GetMultipleAccounts(ctx context.Context, keys []solana.PublicKey) (uint64, [][]byte, error)
+}
+
+// RPCFilter is a getProgramAccounts filter, either on the account data size or
+// on bytes at an offset of the data.
+type RPCFilter struct {
+	Memcmp   *RPCFilterMemcmp `json:"memcmp,omitempty"`
+	DataSize uint64           `json:"dataSize,omitempty"`
+}
+
+type RPCFilterMemcmp struct {
+	Offset uint64        `json:"offset"`
+	Bytes  solana.Base58 `json:"bytes"`
+}
+
+// ProgramAccount is an account returned by getProgramAccounts.
+type ProgramAccount struct {
+	Address solana.PublicKey
+	Data    []byte
+}
+
+// HTTPRPCClient calls a Solana JSON-RPC endpoint over HTTP.
+type HTTPRPCClient struct {
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+func NewHTTPRPCClient(endpoint string) *HTTPRPCClient {
+	return &HTTPRPCClient{Endpoint: endpoint, HTTPClient: http.DefaultClient}
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcContext struct {
+	Slot uint64 `json:"slot"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// rpcAccount is the account layout of the RPC responses, with base64 encoded
+// data.
+type rpcAccount struct {
+	Data []string `json:"data"`
+}
+
+func (a *rpcAccount) decodeData() ([]byte, error) {
+	if len(a.Data) != 2 || a.Data[1] != "base64" {
+		return nil, errors.New("account data must be base64 encoded")
+	}
+	return base64.StdEncoding.DecodeString(a.Data[0])
+}
+
+// call sends a request for method and decodes its result into result.
+func (c *HTTPRPCClient) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	httpResponse, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: http status %d", method, httpResponse.StatusCode)
+	}
+
+	var response rpcResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: rpc error %d: %s", method, response.Error.Code, response.Error.Message)
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+func (c *HTTPRPCClient) GetProgramAccounts(ctx context.Context, program solana.PublicKey, filters []RPCFilter) ([]ProgramAccount, error) {
+	config := map[string]interface{}{"encoding": "base64"}
+	if len(filters) > 0 {
+		config["filters"] = filters
+	}
+
+	var result []struct {
+		Pubkey  solana.PublicKey `json:"pubkey"`
+		Account rpcAccount       `json:"account"`
+	}
+	if err := c.call(ctx, "getProgramAccounts", []interface{}{program, config}, &result); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]ProgramAccount, 0, len(result))
+	for _, r := range result {
+		data, err := r.Account.decodeData()
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, ProgramAccount{Address: r.Pubkey, Data: data})
+	}
+	return accounts, nil
+}
+
+func (c *HTTPRPCClient) GetMultipleAccounts(ctx context.Context, keys []solana.PublicKey) (uint64, [][]byte, error) {
+	config := map[string]interface{}{"encoding": "base64"}
+
+	var result struct {
+		Context rpcContext    `json:"context"`
+		Value   []*rpcAccount `json:"value"`
+	}
+	if err := c.call(ctx, "getMultipleAccounts", []interface{}{keys, config}, &result); err != nil {
+		return 0, nil, err
+	}
+	if len(result.Value) != len(keys) {
+		return 0, nil, fmt.Errorf("getMultipleAccounts: %d accounts for %d keys", len(result.Value), len(keys))
+	}
+
+	accounts := make([][]byte, len(keys))
+	for i, account := range result.Value {
+		if account == nil {
+			continue
+		}
+		data, err := account.decodeData()
+		if err != nil {
+			return 0, nil, err
+		}
+		accounts[i] = data
+	}
+	return result.Context.Slot, accounts, nil
+}
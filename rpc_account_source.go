@@ -0,0 +1,163 @@
+package openbookdexgolang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Most keys a getMultipleAccounts call accepts
+const MAX_MULTIPLE_ACCOUNTS = 100
+
+var (
+	ErrSlotMismatch = errors.New("bids and asks were read at different slots")
+	ErrStaleSlot    = errors.New("accounts are older than the current snapshot")
+)
+
+// SlotAccounts is account data with the slot each account was read at.
+type SlotAccounts struct {
+	Accounts map[solana.PublicKey][]byte
+	Slots    map[solana.PublicKey]uint64
+}
+
+func newSlotAccounts(size int) *SlotAccounts {
+	return &SlotAccounts{
+		Accounts: make(map[solana.PublicKey][]byte, size),
+		Slots:    make(map[solana.PublicKey]uint64, size),
+	}
+}
+
+// RPCAccountSource fetches accounts with chunked getMultipleAccounts calls.
+type RPCAccountSource struct {
+	Client    RPCClient
+	ChunkSize int // Keys per call, MAX_MULTIPLE_ACCOUNTS when 0
+}
+
+func NewRPCAccountSource(client RPCClient) *RPCAccountSource {
+	return &RPCAccountSource{Client: client}
+}
+
+func (s *RPCAccountSource) chunkSize() int {
+	if s.ChunkSize <= 0 || s.ChunkSize > MAX_MULTIPLE_ACCOUNTS {
+		return MAX_MULTIPLE_ACCOUNTS
+	}
+	return s.ChunkSize
+}
+
+// GetAccounts implements AccountSource, dropping the slots.
+func (s *RPCAccountSource) GetAccounts(ctx context.Context, keys []solana.PublicKey) (map[solana.PublicKey][]byte, error) {
+	accounts, err := s.GetSlotAccounts(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	return accounts.Accounts, nil
+}
+
+// GetSlotAccounts fetches the accounts in chunks of keys. Accounts that
+// don't exist are left out.
+func (s *RPCAccountSource) GetSlotAccounts(ctx context.Context, keys []solana.PublicKey) (*SlotAccounts, error) {
+	accounts := newSlotAccounts(len(keys))
+	chunkSize := s.chunkSize()
+	for start := 0; start < len(keys); start += chunkSize {
+		end := min(start+chunkSize, len(keys))
+		if err := s.fetchChunk(ctx, keys[start:end], accounts); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// GetMarketsAccounts fetches the accounts of GetAccountsToUpdate and
+// OracleAccounts of every market. The accounts of a market are fetched by a single call, so that its
+// bids and asks are read at the same slot, and accounts shared by markets are
+// fetched once.
+func (s *RPCAccountSource) GetMarketsAccounts(ctx context.Context, markets []*OpenBookMarket) (*SlotAccounts, error) {
+	chunkSize := s.chunkSize()
+	seen := make(map[solana.PublicKey]bool)
+	chunks := make([][]solana.PublicKey, 0)
+	chunk := make([]solana.PublicKey, 0, chunkSize)
+	for _, obm := range markets {
+		keys := make([]solana.PublicKey, 0)
+		for _, marketKeys := range [][]solana.PublicKey{obm.GetAccountsToUpdate(), obm.OracleAccounts()} {
+			for _, key := range marketKeys {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+		if len(keys) > chunkSize {
+			return nil, fmt.Errorf("market %s has more than %d accounts", obm.key, chunkSize)
+		}
+
+		if len(chunk)+len(keys) > chunkSize {
+			chunks = append(chunks, chunk)
+			chunk = make([]solana.PublicKey, 0, chunkSize)
+		}
+		chunk = append(chunk, keys...)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	accounts := newSlotAccounts(len(seen))
+	for _, chunk := range chunks {
+		if err := s.fetchChunk(ctx, chunk, accounts); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+func (s *RPCAccountSource) fetchChunk(ctx context.Context, keys []solana.PublicKey, accounts *SlotAccounts) error {
+	slot, data, err := s.Client.GetMultipleAccounts(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if data[i] == nil {
+			continue
+		}
+		accounts.Accounts[key] = data[i]
+		accounts.Slots[key] = slot
+	}
+	return nil
+}
+
+// Slot returns the slot the book was read at by the last UpdateAtSlot, 0 when
+// it was never updated with slots.
+func (obm *OpenBookMarket) Slot() uint64 {
+	return obm.slot
+}
+
+// UpdateAtSlot is Update for accounts read at known slots. The accounts are
+// rejected when the bids and asks were read at different slots, which could
+// show a crossed book, or before the slot of the current book.
+func (obm *OpenBookMarket) UpdateAtSlot(accounts *SlotAccounts) error {
+	if obm.isPermissioned {
+		return nil
+	}
+
+	bidsSlot, ok := accounts.Slots[obm.market.Bids]
+	if !ok {
+		return errors.New("bids account not found")
+	}
+	asksSlot, ok := accounts.Slots[obm.market.Asks]
+	if !ok {
+		return errors.New("asks account not found")
+	}
+	if bidsSlot != asksSlot {
+		return ErrSlotMismatch
+	}
+	if bidsSlot < obm.slot {
+		return ErrStaleSlot
+	}
+
+	if err := obm.Update(accounts.Accounts); err != nil {
+		return err
+	}
+	obm.slot = bidsSlot
+	return nil
+}
@@ -0,0 +1,217 @@
+package openbookdexgolang
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// fakeRPCClient serves accounts from memory, reading every call at the slot
+// after the previous one.
+type fakeRPCClient struct {
+	accounts map[solana.PublicKey][]byte
+	slot     uint64
+	calls    [][]solana.PublicKey
+}
+
+func (c *fakeRPCClient) GetProgramAccounts(ctx context.Context, program solana.PublicKey, filters []RPCFilter) ([]ProgramAccount, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeRPCClient) GetMultipleAccounts(ctx context.Context, keys []solana.PublicKey) (uint64, [][]byte, error) {
+	if len(keys) > MAX_MULTIPLE_ACCOUNTS {
+		return 0, nil, errors.New("too many keys")
+	}
+	c.calls = append(c.calls, keys)
+	c.slot++
+	data := make([][]byte, len(keys))
+	for i, key := range keys {
+		data[i] = c.accounts[key]
+	}
+	return c.slot, data, nil
+}
+
+func (c *fakeRPCClient) callSizes() []int {
+	sizes := make([]int, 0, len(c.calls))
+	for _, call := range c.calls {
+		sizes = append(sizes, len(call))
+	}
+	return sizes
+}
+
+func testKeys(n int) []solana.PublicKey {
+	keys := make([]solana.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, solana.PublicKey{0xee, byte(i >> 8), byte(i)})
+	}
+	return keys
+}
+
+func expectSizes(t *testing.T, sizes []int, expected ...int) {
+	t.Helper()
+	if len(sizes) != len(expected) {
+		t.Fatalf("calls of %v keys, expected %v", sizes, expected)
+	}
+	for i := range sizes {
+		if sizes[i] != expected[i] {
+			t.Fatalf("calls of %v keys, expected %v", sizes, expected)
+		}
+	}
+}
+
+func TestGetSlotAccountsChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunkSize int
+		keys      int
+		expected  []int
+	}{
+		{name: "exactly one chunk", chunkSize: 3, keys: 3, expected: []int{3}},
+		{name: "one key over", chunkSize: 3, keys: 4, expected: []int{3, 1}},
+		{name: "exactly the rpc limit", keys: MAX_MULTIPLE_ACCOUNTS, expected: []int{MAX_MULTIPLE_ACCOUNTS}},
+		{name: "one key over the rpc limit", keys: MAX_MULTIPLE_ACCOUNTS + 1, expected: []int{MAX_MULTIPLE_ACCOUNTS, 1}},
+		{name: "chunk size over the rpc limit", chunkSize: MAX_MULTIPLE_ACCOUNTS + 1, keys: MAX_MULTIPLE_ACCOUNTS + 1, expected: []int{MAX_MULTIPLE_ACCOUNTS, 1}},
+		{name: "no keys", chunkSize: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys := testKeys(test.keys)
+			client := &fakeRPCClient{accounts: make(map[solana.PublicKey][]byte)}
+			for _, key := range keys {
+				client.accounts[key] = key.Bytes()
+			}
+			// The last key has no account
+			if len(keys) > 0 {
+				delete(client.accounts, keys[len(keys)-1])
+			}
+
+			source := &RPCAccountSource{Client: client, ChunkSize: test.chunkSize}
+			accounts, err := source.GetSlotAccounts(context.Background(), keys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectSizes(t, client.callSizes(), test.expected...)
+			if len(keys) > 0 && len(accounts.Accounts) != len(keys)-1 {
+				t.Fatalf("%d accounts, expected %d", len(accounts.Accounts), len(keys)-1)
+			}
+			for key, data := range accounts.Accounts {
+				if !bytes.Equal(data, key.Bytes()) {
+					t.Fatalf("data of %s is %v", key, data)
+				}
+			}
+		})
+	}
+}
+
+// newSlotTestMarket returns a market whose accounts are all distinct, but for
+// the Clock sysvar, and puts their data in accounts.
+func newSlotTestMarket(t *testing.T, n byte, accounts map[solana.PublicKey][]byte) *OpenBookMarket {
+	t.Helper()
+	market := newTestMarketAccount()
+	market.Bids = solana.PublicKey{n, 1}
+	market.Asks = solana.PublicKey{n, 2}
+	market.EventHeap = solana.PublicKey{n, 3}
+	market.MarketBaseVault = solana.PublicKey{n, 4}
+	market.MarketQuoteVault = solana.PublicKey{n, 5}
+
+	book, err := BuildOrderbook([]BookOrder{bid(99, 1), ask(101, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts[market.Bids] = encodeTestAccount(t, bookSideDiscriminator, book.Bids)
+	accounts[market.Asks] = encodeTestAccount(t, bookSideDiscriminator, book.Asks)
+	accounts[market.EventHeap] = encodeTestAccount(t, eventHeapDiscriminator, &EventHeap{})
+	buf := bytes.NewBuffer(nil)
+	if err := bin.NewBinEncoder(buf).Encode(&Clock{UnixTimestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	accounts[solana.SysVarClockPubkey] = buf.Bytes()
+	return NewOpenBookMarket(solana.PublicKey{n}, market)
+}
+
+func TestGetMarketsAccountsChunks(t *testing.T) {
+	// Two markets of 6 accounts, sharing the Clock sysvar
+	accounts := make(map[solana.PublicKey][]byte)
+	markets := []*OpenBookMarket{newSlotTestMarket(t, 1, accounts), newSlotTestMarket(t, 2, accounts)}
+
+	tests := []struct {
+		name      string
+		chunkSize int
+		expected  []int
+	}{
+		{name: "both markets in one call", chunkSize: 11, expected: []int{11}},
+		{name: "one key short of both markets", chunkSize: 10, expected: []int{6, 5}},
+		{name: "exactly one market", chunkSize: 6, expected: []int{6, 5}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeRPCClient{accounts: accounts}
+			source := &RPCAccountSource{Client: client, ChunkSize: test.chunkSize}
+			slotAccounts, err := source.GetMarketsAccounts(context.Background(), markets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectSizes(t, client.callSizes(), test.expected...)
+
+			// Each call is at its own slot, the markets still read their bids
+			// and asks at one
+			for _, obm := range markets {
+				if err := obm.UpdateAtSlot(slotAccounts); err != nil {
+					t.Fatalf("market %s: %v", obm.Key(), err)
+				}
+			}
+		})
+	}
+
+	// A market with more accounts than a call takes
+	source := &RPCAccountSource{Client: &fakeRPCClient{accounts: accounts}, ChunkSize: 5}
+	if _, err := source.GetMarketsAccounts(context.Background(), markets); err == nil {
+		t.Fatal("expected an error for a market larger than a call")
+	}
+}
+
+func TestUpdateAtSlot(t *testing.T) {
+	accounts := make(map[solana.PublicKey][]byte)
+	obm := newSlotTestMarket(t, 1, accounts)
+	slots := func(bids, asks uint64) *SlotAccounts {
+		slotAccounts := &SlotAccounts{Accounts: accounts, Slots: make(map[solana.PublicKey]uint64)}
+		for key := range accounts {
+			slotAccounts.Slots[key] = bids
+		}
+		slotAccounts.Slots[obm.market.Asks] = asks
+		return slotAccounts
+	}
+
+	if err := obm.UpdateAtSlot(slots(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if obm.Slot() != 10 || obm.Timestamp() != 1 {
+		t.Fatalf("slot %d timestamp %d, expected 10 and 1", obm.Slot(), obm.Timestamp())
+	}
+
+	// Bids and asks read at different slots could show a crossed book
+	if err := obm.UpdateAtSlot(slots(12, 11)); !errors.Is(err, ErrSlotMismatch) {
+		t.Fatalf("error %v, expected ErrSlotMismatch", err)
+	}
+	if err := obm.UpdateAtSlot(slots(11, 12)); !errors.Is(err, ErrSlotMismatch) {
+		t.Fatalf("error %v, expected ErrSlotMismatch", err)
+	}
+	if obm.Slot() != 10 {
+		t.Fatalf("slot %d after rejected updates, expected 10", obm.Slot())
+	}
+
+	if err := obm.UpdateAtSlot(slots(9, 9)); !errors.Is(err, ErrStaleSlot) {
+		t.Fatalf("error %v, expected ErrStaleSlot", err)
+	}
+	if err := obm.UpdateAtSlot(slots(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := obm.UpdateAtSlot(slots(11, 11)); err != nil || obm.Slot() != 11 {
+		t.Fatalf("error %v at slot %d, expected none at 11", err, obm.Slot())
+	}
+}
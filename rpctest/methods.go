@@ -0,0 +1,270 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcContext struct {
+	Slot uint64 `json:"slot"`
+}
+
+// contextResult is the result of methods answering with the slot they were
+// served at.
+type contextResult struct {
+	Context rpcContext  `json:"context"`
+	Value   interface{} `json:"value"`
+}
+
+type rpcAccount struct {
+	Data       [2]string        `json:"data"`
+	Executable bool             `json:"executable"`
+	Lamports   uint64           `json:"lamports"`
+	Owner      solana.PublicKey `json:"owner"`
+	RentEpoch  uint64           `json:"rentEpoch"`
+	Space      int              `json:"space"`
+}
+
+type programAccount struct {
+	Pubkey  solana.PublicKey `json:"pubkey"`
+	Account *rpcAccount      `json:"account"`
+}
+
+func newRPCAccount(account *Account) *rpcAccount {
+	return &rpcAccount{
+		Data:     [2]string{base64.StdEncoding.EncodeToString(account.Data), "base64"},
+		Lamports: account.Lamports,
+		Owner:    account.Owner,
+		Space:    len(account.Data),
+	}
+}
+
+// accountConfig is the configuration object of the account methods.
+// Commitments are accepted and ignored.
+type accountConfig struct {
+	Encoding    string      `json:"encoding"`
+	Commitment  string      `json:"commitment"`
+	Filters     []rpcFilter `json:"filters"`
+	WithContext bool        `json:"withContext"`
+}
+
+func (c *accountConfig) check() error {
+	if c.Encoding != "" && c.Encoding != "base64" {
+		return errors.New("only base64 encoding is supported")
+	}
+	return nil
+}
+
+type rpcFilter struct {
+	Memcmp *struct {
+		Offset   uint64 `json:"offset"`
+		Bytes    string `json:"bytes"`
+		Encoding string `json:"encoding"`
+	} `json:"memcmp"`
+	DataSize *uint64 `json:"dataSize"`
+}
+
+// matcher returns a function reporting whether account data passes the filter.
+func (f *rpcFilter) matcher() (func(data []byte) bool, error) {
+	switch {
+	case f.DataSize != nil && f.Memcmp == nil:
+		size := *f.DataSize
+		return func(data []byte) bool {
+			return uint64(len(data)) == size
+		}, nil
+	case f.Memcmp != nil && f.DataSize == nil:
+		var expected []byte
+		var err error
+		switch f.Memcmp.Encoding {
+		case "", "base58":
+			expected, err = base58.Decode(f.Memcmp.Bytes)
+		case "base64":
+			expected, err = base64.StdEncoding.DecodeString(f.Memcmp.Bytes)
+		default:
+			err = errors.New("unsupported memcmp encoding " + f.Memcmp.Encoding)
+		}
+		if err != nil {
+			return nil, err
+		}
+		offset := f.Memcmp.Offset
+		return func(data []byte) bool {
+			// Compared without adding to offset, which could overflow
+			return offset <= uint64(len(data)) && uint64(len(expected)) <= uint64(len(data))-offset &&
+				bytes.Equal(data[offset:offset+uint64(len(expected))], expected)
+		}, nil
+	default:
+		return nil, errors.New("filter must have exactly one of memcmp and dataSize")
+	}
+}
+
+// decodeParams decodes the positional parameters into targets, of which the
+// first required ones must be given.
+func decodeParams(raw json.RawMessage, required int, targets ...interface{}) error {
+	var params []json.RawMessage
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return err
+		}
+	}
+	if len(params) < required {
+		return errors.New("missing parameters")
+	}
+	if len(params) > len(targets) {
+		return errors.New("too many parameters")
+	}
+	for i, param := range params {
+		if err := json.Unmarshal(param, targets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle answers an HTTP request.
+func (s *Server) handle(request *rpcRequest) rpcResponse {
+	var result interface{}
+	var err error
+	switch request.Method {
+	case "getSlot":
+		result = s.Slot()
+	case "getAccountInfo":
+		result, err = s.getAccountInfo(request.Params)
+	case "getMultipleAccounts":
+		result, err = s.getMultipleAccounts(request.Params)
+	case "getProgramAccounts":
+		result, err = s.getProgramAccounts(request.Params)
+	default:
+		return errorResponse(request, codeMethodNotFound, "method not found: "+request.Method)
+	}
+	if err != nil {
+		return errorResponse(request, codeInvalidParams, err.Error())
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: result}
+}
+
+func errorResponse(request *rpcRequest, code int, message string) rpcResponse {
+	return rpcResponse{JSONRPC: "2.0", ID: request.ID, Error: &rpcError{Code: code, Message: message}}
+}
+
+func (s *Server) getAccountInfo(params json.RawMessage) (interface{}, error) {
+	var address solana.PublicKey
+	var config accountConfig
+	if err := decodeParams(params, 1, &address, &config); err != nil {
+		return nil, err
+	}
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := contextResult{Context: rpcContext{Slot: s.slot}, Value: (*rpcAccount)(nil)}
+	if account, ok := s.accounts[address]; ok {
+		result.Value = newRPCAccount(&account)
+	}
+	return result, nil
+}
+
+func (s *Server) getMultipleAccounts(params json.RawMessage) (interface{}, error) {
+	var addresses []solana.PublicKey
+	var config accountConfig
+	if err := decodeParams(params, 1, &addresses, &config); err != nil {
+		return nil, err
+	}
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := make([]*rpcAccount, len(addresses))
+	for i, address := range addresses {
+		if account, ok := s.accounts[address]; ok {
+			accounts[i] = newRPCAccount(&account)
+		}
+	}
+	return contextResult{Context: rpcContext{Slot: s.slot}, Value: accounts}, nil
+}
+
+func (s *Server) getProgramAccounts(params json.RawMessage) (interface{}, error) {
+	var program solana.PublicKey
+	var config accountConfig
+	if err := decodeParams(params, 1, &program, &config); err != nil {
+		return nil, err
+	}
+	if err := config.check(); err != nil {
+		return nil, err
+	}
+	matchers := make([]func(data []byte) bool, 0, len(config.Filters))
+	for i := range config.Filters {
+		matcher, err := config.Filters[i].matcher()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := make([]programAccount, 0)
+	for address, account := range s.accounts {
+		if account.Owner != program || !matchesAll(matchers, account.Data) {
+			continue
+		}
+		accounts = append(accounts, programAccount{Pubkey: address, Account: newRPCAccount(&account)})
+	}
+	// Map order is random, answer in a stable one
+	sortProgramAccounts(accounts)
+
+	if config.WithContext {
+		return contextResult{Context: rpcContext{Slot: s.slot}, Value: accounts}, nil
+	}
+	return accounts, nil
+}
+
+func matchesAll(matchers []func(data []byte) bool, data []byte) bool {
+	for _, match := range matchers {
+		if !match(data) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortProgramAccounts(accounts []programAccount) {
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Pubkey[:], accounts[j].Pubkey[:]) < 0
+	})
+}
@@ -0,0 +1,246 @@
+package rpctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/mr-tron/base58"
+)
+
+var (
+	testProgram = solana.PublicKey{0xf0}
+	testOwner   = solana.PublicKey{0xf1}
+)
+
+type testResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// call posts a request with the given raw params and decodes the response.
+func call(t *testing.T, server *Server, method string, params string) testResponse {
+	t.Helper()
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`
+	httpResponse, err := http.Post(server.URL(), "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResponse.Body.Close()
+
+	var response testResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func callResult(t *testing.T, server *Server, method string, params string, result interface{}) {
+	t.Helper()
+	response := call(t, server, method, params)
+	if response.Error != nil {
+		t.Fatalf("%s: error %d: %s", method, response.Error.Code, response.Error.Message)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func expectError(t *testing.T, server *Server, method string, params string, code int) {
+	t.Helper()
+	response := call(t, server, method, params)
+	if response.Error == nil {
+		t.Fatalf("%s %s: result %s, expected error %d", method, params, response.Result, code)
+	}
+	if response.Error.Code != code {
+		t.Fatalf("%s %s: error %d, expected %d", method, params, response.Error.Code, code)
+	}
+}
+
+func quote(key solana.PublicKey) string {
+	return `"` + key.String() + `"`
+}
+
+func (a *rpcAccount) data(t *testing.T) []byte {
+	t.Helper()
+	if a.Data[1] != "base64" {
+		t.Fatalf("data encoded as %q", a.Data[1])
+	}
+	data, err := base64.StdEncoding.DecodeString(a.Data[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestServer(t *testing.T) *Server {
+	server := NewServer()
+	t.Cleanup(server.Close)
+	server.SetSlot(42)
+	return server
+}
+
+func TestGetAccountInfo(t *testing.T) {
+	server := newTestServer(t)
+	address := solana.PublicKey{1}
+	server.SetAccount(address, Account{Owner: testOwner, Lamports: 7, Data: []byte{1, 2, 3}})
+
+	var result struct {
+		Context rpcContext  `json:"context"`
+		Value   *rpcAccount `json:"value"`
+	}
+	callResult(t, server, "getAccountInfo", `[`+quote(address)+`, {"encoding": "base64"}]`, &result)
+	if result.Context.Slot != 42 || result.Value == nil {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !bytes.Equal(result.Value.data(t), []byte{1, 2, 3}) || result.Value.Owner != testOwner || result.Value.Lamports != 7 || result.Value.Space != 3 {
+		t.Fatalf("unexpected account %+v", result.Value)
+	}
+
+	// Missing accounts are null
+	result.Value = nil
+	callResult(t, server, "getAccountInfo", `[`+quote(solana.PublicKey{2})+`]`, &result)
+	if result.Value != nil {
+		t.Fatalf("account %+v, expected none", result.Value)
+	}
+}
+
+func TestGetMultipleAccounts(t *testing.T) {
+	server := newTestServer(t)
+	first, missing, last := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}
+	server.SetAccount(first, Account{Owner: testOwner, Data: []byte{1}})
+	server.SetAccount(last, Account{Owner: testOwner, Data: []byte{3}})
+
+	var result struct {
+		Context rpcContext    `json:"context"`
+		Value   []*rpcAccount `json:"value"`
+	}
+	params := `[[` + quote(last) + `,` + quote(missing) + `,` + quote(first) + `], {"encoding": "base64", "commitment": "confirmed"}]`
+	callResult(t, server, "getMultipleAccounts", params, &result)
+	if result.Context.Slot != 42 || len(result.Value) != 3 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if result.Value[1] != nil {
+		t.Fatalf("missing account returned as %+v", result.Value[1])
+	}
+	if !bytes.Equal(result.Value[0].data(t), []byte{3}) || !bytes.Equal(result.Value[2].data(t), []byte{1}) {
+		t.Fatal("accounts not returned in the order of the keys")
+	}
+}
+
+func TestGetProgramAccounts(t *testing.T) {
+	server := newTestServer(t)
+	a, b, c := solana.PublicKey{1}, solana.PublicKey{2}, solana.PublicKey{3}
+	server.SetAccount(c, Account{Owner: testProgram, Data: []byte{9, 1, 2, 3}})
+	server.SetAccount(b, Account{Owner: testProgram, Data: []byte{9, 1, 2}})
+	server.SetAccount(a, Account{Owner: testProgram, Data: []byte{8, 1, 2, 3}})
+	server.SetAccount(solana.PublicKey{4}, Account{Owner: testOwner, Data: []byte{9, 1, 2, 3}})
+
+	base58Bytes := func(data ...byte) string { return `"` + base58.Encode(data) + `"` }
+	tests := []struct {
+		name     string
+		filters  string
+		expected []solana.PublicKey
+	}{
+		{name: "no filters", filters: `[]`, expected: []solana.PublicKey{a, b, c}},
+		{name: "data size", filters: `[{"dataSize": 4}]`, expected: []solana.PublicKey{a, c}},
+		{name: "memcmp", filters: `[{"memcmp": {"offset": 0, "bytes": ` + base58Bytes(9) + `}}]`, expected: []solana.PublicKey{b, c}},
+		{name: "memcmp base64", filters: `[{"memcmp": {"offset": 1, "bytes": "AQID", "encoding": "base64"}}]`, expected: []solana.PublicKey{a, c}},
+		{name: "every filter", filters: `[{"dataSize": 4}, {"memcmp": {"offset": 0, "bytes": ` + base58Bytes(9) + `}}]`, expected: []solana.PublicKey{c}},
+		{name: "memcmp at the end", filters: `[{"memcmp": {"offset": 3, "bytes": ` + base58Bytes(3) + `}}]`, expected: []solana.PublicKey{a, c}},
+		{name: "memcmp past the end", filters: `[{"memcmp": {"offset": 4, "bytes": ` + base58Bytes(3) + `}}]`, expected: []solana.PublicKey{}},
+		{name: "memcmp at the largest offset", filters: `[{"memcmp": {"offset": 18446744073709551615, "bytes": ` + base58Bytes(1, 2) + `}}]`, expected: []solana.PublicKey{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var result []programAccount
+			callResult(t, server, "getProgramAccounts", `[`+quote(testProgram)+`, {"encoding": "base64", "filters": `+test.filters+`}]`, &result)
+			addresses := make([]solana.PublicKey, 0, len(result))
+			for _, account := range result {
+				addresses = append(addresses, account.Pubkey)
+			}
+			if len(addresses) != len(test.expected) {
+				t.Fatalf("found %v, expected %v", addresses, test.expected)
+			}
+			for i := range addresses {
+				if addresses[i] != test.expected[i] {
+					t.Fatalf("found %v, expected %v", addresses, test.expected)
+				}
+			}
+		})
+	}
+
+	var result struct {
+		Context rpcContext       `json:"context"`
+		Value   []programAccount `json:"value"`
+	}
+	callResult(t, server, "getProgramAccounts", `[`+quote(testProgram)+`, {"withContext": true, "filters": [{"dataSize": 3}]}]`, &result)
+	if result.Context.Slot != 42 || len(result.Value) != 1 || result.Value[0].Pubkey != b {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestMemcmpOffsetOverflow(t *testing.T) {
+	filter := rpcFilter{}
+	filter.Memcmp = &struct {
+		Offset   uint64 `json:"offset"`
+		Bytes    string `json:"bytes"`
+		Encoding string `json:"encoding"`
+	}{Offset: math.MaxUint64 - 1, Bytes: base58.Encode([]byte{1, 2, 3})}
+	match, err := filter.matcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match([]byte{1, 2, 3}) {
+		t.Fatal("matched bytes past the end of the data")
+	}
+}
+
+func TestMalformedRequests(t *testing.T) {
+	server := newTestServer(t)
+	program := quote(testProgram)
+
+	tests := []struct {
+		name   string
+		method string
+		params string
+		code   int
+	}{
+		{name: "unknown method", method: "getBalance", params: `[]`, code: codeMethodNotFound},
+		{name: "account info without address", method: "getAccountInfo", params: `[]`, code: codeInvalidParams},
+		{name: "invalid address", method: "getAccountInfo", params: `["not a key"]`, code: codeInvalidParams},
+		{name: "unsupported encoding", method: "getAccountInfo", params: `[` + program + `, {"encoding": "jsonParsed"}]`, code: codeInvalidParams},
+		{name: "too many params", method: "getAccountInfo", params: `[` + program + `, {}, {}]`, code: codeInvalidParams},
+		{name: "params not a list", method: "getMultipleAccounts", params: `{"keys": []}`, code: codeInvalidParams},
+		{name: "keys not a list", method: "getMultipleAccounts", params: `[` + program + `]`, code: codeInvalidParams},
+		{name: "filter with both kinds", method: "getProgramAccounts", params: `[` + program + `, {"filters": [{"dataSize": 1, "memcmp": {"offset": 0, "bytes": "2"}}]}]`, code: codeInvalidParams},
+		{name: "empty filter", method: "getProgramAccounts", params: `[` + program + `, {"filters": [{}]}]`, code: codeInvalidParams},
+		{name: "invalid base58", method: "getProgramAccounts", params: `[` + program + `, {"filters": [{"memcmp": {"offset": 0, "bytes": "0OIl"}}]}]`, code: codeInvalidParams},
+		{name: "unknown memcmp encoding", method: "getProgramAccounts", params: `[` + program + `, {"filters": [{"memcmp": {"offset": 0, "bytes": "2", "encoding": "hex"}}]}]`, code: codeInvalidParams},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expectError(t, server, test.method, test.params, test.code)
+		})
+	}
+
+	// Bodies that aren't JSON
+	httpResponse, err := http.Post(server.URL(), "application/json", strings.NewReader("{"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResponse.Body.Close()
+	var response testResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error == nil || response.Error.Code != codeParseError {
+		t.Fatalf("unexpected response %+v", response)
+	}
+}
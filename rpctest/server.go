@@ -0,0 +1,334 @@
+// Package rpctest serves a fake Solana JSON-RPC API from account dumps, so
+// that OpenBook integrations can run without a network.
+//
+// The server answers getAccountInfo, getMultipleAccounts, getProgramAccounts,
+// getSlot, accountSubscribe and accountUnsubscribe, with base64 encoded
+// account data only. Websocket subscriptions are served on the same address as
+// HTTP requests, see Server.WSURL.
+//
+// Accounts are only ever changed through the Server: by loading dumps, by
+// setting them directly, or by scheduling updates applied once the slot they
+// are scheduled at is reached.
+package rpctest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	openbook "github.com/texora/openbook-dex-golang"
+)
+
+// Account is the state of an account served by the Server.
+type Account struct {
+	Owner    solana.PublicKey
+	Lamports uint64
+	Data     []byte
+}
+
+// Update is a scheduled change of an account. A nil Account deletes it.
+type Update struct {
+	Slot    uint64
+	Address solana.PublicKey
+	Account *Account
+}
+
+// Server is a fake JSON-RPC node. It is safe for concurrent use.
+type Server struct {
+	httpServer *httptest.Server
+
+	// Serializes changes, so that their notifications are sent in order
+	changeMu sync.Mutex
+
+	mu       sync.Mutex
+	slot     uint64
+	accounts map[solana.PublicKey]Account
+	// Pending updates, sorted by slot
+	updates []Update
+
+	conns              map[*wsConn]bool
+	subscriptions      map[uint64]*subscription
+	nextSubscriptionID uint64
+}
+
+// NewServer starts a server with no accounts at slot 1. It must be closed
+// with Close.
+func NewServer() *Server {
+	s := &Server{
+		slot:          1,
+		accounts:      make(map[solana.PublicKey]Account),
+		conns:         make(map[*wsConn]bool),
+		subscriptions: make(map[uint64]*subscription),
+	}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// URL returns the address of the HTTP endpoint.
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// WSURL returns the address of the websocket endpoint.
+func (s *Server) WSURL() string {
+	return "ws" + strings.TrimPrefix(s.httpServer.URL, "http")
+}
+
+// Close closes every websocket connection and stops the server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[*wsConn]bool)
+	s.subscriptions = make(map[uint64]*subscription)
+	s.mu.Unlock()
+
+	for conn := range conns {
+		conn.close()
+	}
+	s.httpServer.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.serveWebsocket(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeResponse(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+	writeResponse(w, s.handle(&request))
+}
+
+func writeResponse(w http.ResponseWriter, response rpcResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Slot returns the current slot.
+func (s *Server) Slot() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot
+}
+
+// Account returns the current state of an account.
+func (s *Server) Account(address solana.PublicKey) (Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account, ok := s.accounts[address]
+	return account, ok
+}
+
+// SetAccount changes an account at the current slot, notifying its
+// subscribers.
+func (s *Server) SetAccount(address solana.PublicKey, account Account) {
+	s.apply([]Update{{Address: address, Account: &account}})
+}
+
+// DeleteAccount removes an account at the current slot, notifying its
+// subscribers.
+func (s *Server) DeleteAccount(address solana.PublicKey) {
+	s.apply([]Update{{Address: address}})
+}
+
+// Schedule queues updates to apply when the slot reaches theirs. Updates at
+// or before the current slot are applied immediately, all of them in the order
+// given.
+func (s *Server) Schedule(updates ...Update) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.mu.Lock()
+	s.updates = append(s.updates, updates...)
+	sort.SliceStable(s.updates, func(i, j int) bool {
+		return s.updates[i].Slot < s.updates[j].Slot
+	})
+	notifications := s.applyDueLocked()
+	s.mu.Unlock()
+
+	s.notify(notifications)
+}
+
+// AdvanceSlot moves the slot forward by n, applying the updates scheduled up
+// to the new slot. It returns the new slot.
+func (s *Server) AdvanceSlot(n uint64) uint64 {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.mu.Lock()
+	s.slot += n
+	slot := s.slot
+	notifications := s.applyDueLocked()
+	s.mu.Unlock()
+
+	s.notify(notifications)
+	return slot
+}
+
+// SetSlot moves the slot forward to slot, see AdvanceSlot. The slot never
+// goes backwards.
+func (s *Server) SetSlot(slot uint64) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.mu.Lock()
+	if slot > s.slot {
+		s.slot = slot
+	}
+	notifications := s.applyDueLocked()
+	s.mu.Unlock()
+
+	s.notify(notifications)
+}
+
+// apply makes the updates at the current slot.
+func (s *Server) apply(updates []Update) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.mu.Lock()
+	notifications := make([]notification, 0, len(updates))
+	for _, update := range updates {
+		update.Slot = s.slot
+		notifications = append(notifications, s.applyLocked(update)...)
+	}
+	s.mu.Unlock()
+
+	s.notify(notifications)
+}
+
+// applyDueLocked applies the pending updates up to the current slot. Callers
+// must hold mu.
+func (s *Server) applyDueLocked() []notification {
+	notifications := make([]notification, 0)
+	due := 0
+	for due < len(s.updates) && s.updates[due].Slot <= s.slot {
+		notifications = append(notifications, s.applyLocked(s.updates[due])...)
+		due++
+	}
+	s.updates = s.updates[due:]
+	return notifications
+}
+
+// applyLocked changes the account and returns the notifications to send to
+// its subscribers. Callers must hold mu.
+func (s *Server) applyLocked(update Update) []notification {
+	if update.Account == nil {
+		delete(s.accounts, update.Address)
+	} else {
+		account := *update.Account
+		account.Data = append([]byte(nil), account.Data...)
+		s.accounts[update.Address] = account
+	}
+	return s.notificationsLocked(update.Address, update.Slot)
+}
+
+// LoadDir sets every account dumped in dir at the current slot, see
+// ReadAccountDir.
+func (s *Server) LoadDir(dir string) error {
+	updates, err := ReadAccountDir(dir)
+	if err != nil {
+		return err
+	}
+	s.apply(updates)
+	return nil
+}
+
+// ScheduleDir schedules the accounts dumped in the subdirectories of dir,
+// each named after the slot its accounts are set at:
+//
+//	dir/120/<address>.json
+//	dir/125/<address>.json
+func (s *Server) ScheduleDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	updates := make([]Update, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		slot, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if err != nil {
+			return errors.New("scheduled directory is not named after a slot: " + entry.Name())
+		}
+		slotUpdates, err := ReadAccountDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		for i := range slotUpdates {
+			slotUpdates[i].Slot = slot
+		}
+		updates = append(updates, slotUpdates...)
+	}
+	s.Schedule(updates...)
+	return nil
+}
+
+// accountFile is the part of `solana account --output json` dumps not read
+// by openbook.ParseAccountFile.
+type accountFile struct {
+	Account struct {
+		Owner    string `json:"owner"`
+		Lamports uint64 `json:"lamports"`
+	} `json:"account"`
+}
+
+// ReadAccountDir reads the account dumps in dir, in any format accepted by
+// openbook.LoadAccountFile. Accounts are at the address of the dump, or the
+// one the file is named after, <address>.json. Dumps without an owner are
+// owned by the OpenBook v2 program. The updates have no slot set.
+func ReadAccountDir(dir string) ([]Update, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]Update, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		address, data, err := openbook.ParseAccountFile(content)
+		if err != nil {
+			return nil, errors.New(path + ": " + err.Error())
+		}
+		if address.IsZero() {
+			address, err = solana.PublicKeyFromBase58(strings.TrimSuffix(entry.Name(), ".json"))
+			if err != nil {
+				return nil, errors.New(path + ": file has no address and is not named after one")
+			}
+		}
+
+		account := Account{Owner: openbook.OPENBOOK_V2_PROGRAM_ID, Data: data}
+		var file accountFile
+		if json.Unmarshal(content, &file) == nil && file.Account.Owner != "" {
+			account.Owner, err = solana.PublicKeyFromBase58(file.Account.Owner)
+			if err != nil {
+				return nil, errors.New(path + ": " + err.Error())
+			}
+			account.Lamports = file.Account.Lamports
+		}
+		updates = append(updates, Update{Address: address, Account: &account})
+	}
+	return updates, nil
+}
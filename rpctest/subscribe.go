@@ -0,0 +1,167 @@
+package rpctest
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsConn is a websocket connection, writes from the read loop and from
+// notifications are serialized.
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *wsConn) writeJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+func (c *wsConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	c.conn.Close()
+}
+
+type subscription struct {
+	id      uint64
+	address solana.PublicKey
+	conn    *wsConn
+}
+
+// notification is an accountNotification to send once mu is released.
+type notification struct {
+	conn    *wsConn
+	message interface{}
+}
+
+type accountNotification struct {
+	JSONRPC string                    `json:"jsonrpc"`
+	Method  string                    `json:"method"`
+	Params  accountNotificationParams `json:"params"`
+}
+
+type accountNotificationParams struct {
+	Result       contextResult `json:"result"`
+	Subscription uint64        `json:"subscription"`
+}
+
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	ws := &wsConn{conn: conn}
+	s.mu.Lock()
+	s.conns[ws] = true
+	s.mu.Unlock()
+	defer s.closeConn(ws)
+
+	for {
+		var request rpcRequest
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+
+		var response rpcResponse
+		switch request.Method {
+		case "accountSubscribe":
+			response = s.accountSubscribe(ws, &request)
+		case "accountUnsubscribe":
+			response = s.accountUnsubscribe(&request)
+		default:
+			response = s.handle(&request)
+		}
+		if err := ws.writeJSON(response); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) accountSubscribe(conn *wsConn, request *rpcRequest) rpcResponse {
+	var address solana.PublicKey
+	var config accountConfig
+	if err := decodeParams(request.Params, 1, &address, &config); err != nil {
+		return errorResponse(request, codeInvalidParams, err.Error())
+	}
+	if err := config.check(); err != nil {
+		return errorResponse(request, codeInvalidParams, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSubscriptionID++
+	id := s.nextSubscriptionID
+	s.subscriptions[id] = &subscription{id: id, address: address, conn: conn}
+	return rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: id}
+}
+
+func (s *Server) accountUnsubscribe(request *rpcRequest) rpcResponse {
+	var id uint64
+	if err := decodeParams(request.Params, 1, &id); err != nil {
+		return errorResponse(request, codeInvalidParams, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.subscriptions[id]
+	delete(s.subscriptions, id)
+	return rpcResponse{JSONRPC: "2.0", ID: request.ID, Result: ok}
+}
+
+// closeConn drops a connection and its subscriptions.
+func (s *Server) closeConn(conn *wsConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	for id, sub := range s.subscriptions {
+		if sub.conn == conn {
+			delete(s.subscriptions, id)
+		}
+	}
+	conn.conn.Close()
+}
+
+// notificationsLocked returns the notifications of the current state of the
+// account, changed at slot, for its subscribers. Callers must hold mu.
+func (s *Server) notificationsLocked(address solana.PublicKey, slot uint64) []notification {
+	notifications := make([]notification, 0)
+	for _, sub := range s.subscriptions {
+		if sub.address != address {
+			continue
+		}
+
+		result := contextResult{Context: rpcContext{Slot: slot}, Value: (*rpcAccount)(nil)}
+		if account, ok := s.accounts[address]; ok {
+			result.Value = newRPCAccount(&account)
+		}
+		notifications = append(notifications, notification{
+			conn: sub.conn,
+			message: accountNotification{
+				JSONRPC: "2.0",
+				Method:  "accountNotification",
+				Params:  accountNotificationParams{Result: result, Subscription: sub.id},
+			},
+		})
+	}
+	return notifications
+}
+
+// notify sends the notifications, the connections failing are closed and
+// their subscriptions dropped by their read loop.
+func (s *Server) notify(notifications []notification) {
+	for _, n := range notifications {
+		if err := n.conn.writeJSON(n.message); err != nil {
+			n.conn.conn.Close()
+		}
+	}
+}
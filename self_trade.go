@@ -0,0 +1,49 @@
+package openbookdexgolang
+
+import (
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SelfTradeBehavior is what the program does when a taker matches an order of
+// its own OpenOrders account.
+type SelfTradeBehavior uint8
+
+const (
+	// The match goes through without fees, decrementing both orders
+	DecrementTake SelfTradeBehavior = iota
+	// The resting order is cancelled and matching continues past it
+	CancelProvide
+	// The whole transaction fails
+	AbortTransaction
+)
+
+var ErrWouldSelfTrade = errors.New("would self trade")
+
+// SelfTradeConfig identifies the taker of a simulated take.
+type SelfTradeConfig struct {
+	OpenOrdersAccount solana.PublicKey
+	Behavior          SelfTradeBehavior
+}
+
+// SelfTrade is one of the taker's own resting orders hit by a take.
+type SelfTrade struct {
+	Handle        BookSideOrderHandle
+	Key           OrderId
+	ClientOrderID uint64
+	PriceLots     int64
+	BaseLots      int64 // Base lots matched, or the whole order when cancelled
+	Cancelled     bool
+}
+
+func newSelfTrade(item *BookSideIterItem, baseLots int64, cancelled bool) SelfTrade {
+	return SelfTrade{
+		Handle:        item.Handle,
+		Key:           item.Node.OrderId(),
+		ClientOrderID: item.Node.ClientOrderID,
+		PriceLots:     item.PriceLots,
+		BaseLots:      baseLots,
+		Cancelled:     cancelled,
+	}
+}
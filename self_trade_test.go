@@ -0,0 +1,131 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var testTaker = solana.PublicKey{9}
+
+// ownAsk returns an ask resting on the taker's own account.
+func ownAsk(priceLots, quantity int64, clientOrderID uint64) BookOrder {
+	order := ask(priceLots, quantity)
+	order.Owner = testTaker
+	order.ClientOrderID = clientOrderID
+	return order
+}
+
+func simulateSelfTrade(t *testing.T, market *Market, behavior SelfTradeBehavior, orders ...BookOrder) (TakeSimulation, error) {
+	t.Helper()
+	book, err := BuildOrderbook(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selfTrade := &SelfTradeConfig{OpenOrdersAccount: testTaker, Behavior: behavior}
+	return SimulateTake(book, Bid, market.MaxBaseLots(), market.MaxQuoteLots(), market, nil, 1, TakeOptions{SelfTrade: selfTrade})
+}
+
+func TestDecrementTake(t *testing.T) {
+	market := newTestMarketAccount()
+	market.MakerFee = -1_000
+	take, err := simulateSelfTrade(t, market, DecrementTake, ownAsk(1_000, 5, 42), ask(1_001, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both orders match, only the other maker is rebated
+	if take.TotalBaseLotsTaken != 10 || take.TotalQuoteLotsTaken != 5*1_000+5*1_001 {
+		t.Fatalf("took %d base %d quote, expected 10 base %d quote", take.TotalBaseLotsTaken, take.TotalQuoteLotsTaken, 5*1_000+5*1_001)
+	}
+	if take.MakerRebates != 5 {
+		t.Fatalf("maker rebates %d, expected 5", take.MakerRebates)
+	}
+	if len(take.SelfTrades) != 1 {
+		t.Fatalf("%d self trades, expected 1", len(take.SelfTrades))
+	}
+	selfTrade := take.SelfTrades[0]
+	if selfTrade.Cancelled || selfTrade.BaseLots != 5 || selfTrade.PriceLots != 1_000 || selfTrade.ClientOrderID != 42 {
+		t.Fatalf("self trade %+v, expected 5 lots matched at 1000 of client order 42", selfTrade)
+	}
+}
+
+func TestDecrementTakeCountsTowardsOrderLimit(t *testing.T) {
+	var orders []BookOrder
+	for i := 0; i < MAXIMUM_TAKEN_ORDERS; i++ {
+		orders = append(orders, ownAsk(100, 1, uint64(i)))
+	}
+	orders = append(orders, ask(101, 1))
+
+	take, err := simulateSelfTrade(t, newTestMarketAccount(), DecrementTake, orders...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if take.TotalBaseLotsTaken != MAXIMUM_TAKEN_ORDERS || take.StopReason != StopOrderLimit {
+		t.Fatalf("took %d lots, stopped on %s, expected %d lots and the order limit", take.TotalBaseLotsTaken, take.StopReason, MAXIMUM_TAKEN_ORDERS)
+	}
+	if len(take.SelfTrades) != MAXIMUM_TAKEN_ORDERS {
+		t.Fatalf("%d self trades, expected %d", len(take.SelfTrades), MAXIMUM_TAKEN_ORDERS)
+	}
+}
+
+func TestCancelProvide(t *testing.T) {
+	// The cancelled orders don't count towards the order limit, so every one
+	// of the other orders up to it still matches
+	var orders []BookOrder
+	for i := 0; i < MAXIMUM_TAKEN_ORDERS; i++ {
+		orders = append(orders, ownAsk(100, 2, uint64(i)))
+	}
+	for i := 0; i <= MAXIMUM_TAKEN_ORDERS; i++ {
+		orders = append(orders, ask(101, 1))
+	}
+
+	take, err := simulateSelfTrade(t, newTestMarketAccount(), CancelProvide, orders...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if take.TotalBaseLotsTaken != MAXIMUM_TAKEN_ORDERS || take.TotalQuoteLotsTaken != MAXIMUM_TAKEN_ORDERS*101 {
+		t.Fatalf("took %d base %d quote, expected %d base %d quote", take.TotalBaseLotsTaken, take.TotalQuoteLotsTaken, MAXIMUM_TAKEN_ORDERS, MAXIMUM_TAKEN_ORDERS*101)
+	}
+	if take.StopReason != StopOrderLimit {
+		t.Fatalf("stopped on %s, expected the order limit", take.StopReason)
+	}
+	if len(take.SelfTrades) != MAXIMUM_TAKEN_ORDERS {
+		t.Fatalf("%d self trades, expected %d", len(take.SelfTrades), MAXIMUM_TAKEN_ORDERS)
+	}
+	for _, selfTrade := range take.SelfTrades {
+		if !selfTrade.Cancelled || selfTrade.BaseLots != 2 || selfTrade.PriceLots != 100 {
+			t.Fatalf("self trade %+v, expected the whole order at 100 cancelled", selfTrade)
+		}
+	}
+}
+
+func TestAbortTransaction(t *testing.T) {
+	_, err := simulateSelfTrade(t, newTestMarketAccount(), AbortTransaction, ask(99, 5), ownAsk(100, 5, 0))
+	if !errors.Is(err, ErrWouldSelfTrade) {
+		t.Fatalf("error %v, expected %v", err, ErrWouldSelfTrade)
+	}
+
+	// The quote fails the same way
+	obm := newTestMarket(t, newTestMarketAccount(), ask(99, 5), ownAsk(100, 5, 0))
+	params := &QuoteParams{
+		InAmount:   1_000,
+		InputMint:  testQuoteMint,
+		OutputMint: testBaseMint,
+		SelfTrade:  &SelfTradeConfig{OpenOrdersAccount: testTaker, Behavior: AbortTransaction},
+	}
+	if _, err := obm.Quote(params); !errors.Is(err, ErrWouldSelfTrade) {
+		t.Fatalf("quote error %v, expected %v", err, ErrWouldSelfTrade)
+	}
+
+	// A take that stops before the own order doesn't self trade
+	params.InAmount = 99 * 5
+	quote, err := obm.Quote(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.OutAmount != 5 {
+		t.Fatalf("out %d, expected 5", quote.OutAmount)
+	}
+}
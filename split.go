@@ -0,0 +1,195 @@
+package openbookdexgolang
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SplitAllocation is the part of a split quote sent to one market.
+type SplitAllocation struct {
+	Market   solana.PublicKey
+	InAmount uint64
+	Quote    *Quote
+}
+
+// SplitQuote is an input amount split across several markets of the same
+// pair. The amounts are the sums over the allocations.
+type SplitQuote struct {
+	Allocations        []SplitAllocation
+	InAmount           uint64
+	OutAmount          uint64
+	FeeAmount          uint64
+	NotEnoughLiquidity bool
+}
+
+// splitStep is taking one resting order completely, in native amounts
+// including taker fees. The input is rounded up to whole lots, like Quote
+// converts it.
+type splitStep struct {
+	market    int
+	inAmount  uint64
+	outAmount uint64
+	// Granularity the market accepts input in
+	lotSize uint64
+}
+
+func (s *splitStep) rate() float64 {
+	return float64(s.outAmount) / float64(s.inAmount)
+}
+
+// QuoteSplit splits the input amount across markets trading the same pair to
+// maximise the combined output.
+//
+// Every market's opposing side is walked order by order, at most
+// MAXIMUM_TAKEN_ORDERS per market, and the input is given to the best priced
+// orders first, whichever market they rest on. Each market then quotes the
+// amount it was given. Markets that get nothing are left out.
+func QuoteSplit(markets []*OpenBookMarket, quoteParams *QuoteParams) (*SplitQuote, error) {
+	if len(markets) == 0 {
+		return nil, errors.New("no markets to split across")
+	}
+
+	steps := make([]splitStep, 0)
+	for i, obm := range markets {
+		mints := obm.ReserveMints()
+		if !(mints[0] == quoteParams.InputMint && mints[1] == quoteParams.OutputMint) &&
+			!(mints[0] == quoteParams.OutputMint && mints[1] == quoteParams.InputMint) {
+			return nil, errors.New("market does not trade the quoted pair")
+		}
+		if obm.isPermissioned {
+			continue
+		}
+
+		marketSteps, err := obm.splitSteps(i, quoteParams.InputMint)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, marketSteps...)
+	}
+
+	// The steps of one market get worse as its book is walked, so taking
+	// the best rates first never skips an order of the same market. The
+	// stable sort keeps equally priced orders in book order.
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].rate() > steps[j].rate()
+	})
+
+	// Less than the smallest input lot can't be placed on any market
+	minLotSize := uint64(0)
+	for _, obm := range markets {
+		if obm.isPermissioned {
+			continue
+		}
+		if lotSize := obm.inputLotSize(quoteParams.InputMint); minLotSize == 0 || lotSize < minLotSize {
+			minLotSize = lotSize
+		}
+	}
+
+	inAmounts := make([]uint64, len(markets))
+	remaining := quoteParams.InAmount
+	for _, step := range steps {
+		if remaining < minLotSize {
+			break
+		}
+		take := step.inAmount
+		if take > remaining {
+			// Partially take the order, in whole lots of the market
+			take = remaining - remaining%step.lotSize
+		}
+		inAmounts[step.market] += take
+		remaining -= take
+	}
+
+	// The allocations never exceed the orders walked, so the markets' own
+	// NotEnoughLiquidity would only report quote left over by lot rounding
+	split := &SplitQuote{
+		Allocations:        make([]SplitAllocation, 0),
+		NotEnoughLiquidity: remaining >= minLotSize,
+	}
+	for i, obm := range markets {
+		if inAmounts[i] == 0 {
+			continue
+		}
+
+		quote, err := obm.Quote(&QuoteParams{
+			InAmount:   inAmounts[i],
+			InputMint:  quoteParams.InputMint,
+			OutputMint: quoteParams.OutputMint,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		split.Allocations = append(split.Allocations, SplitAllocation{
+			Market:   obm.Key(),
+			InAmount: inAmounts[i],
+			Quote:    quote,
+		})
+		split.InAmount += quote.InAmount
+		split.OutAmount += quote.OutAmount
+		split.FeeAmount += quote.FeeAmount
+	}
+
+	return split, nil
+}
+
+// splitSteps walks the side a taker paying with inputMint matches against, one
+// step per valid order.
+func (obm *OpenBookMarket) splitSteps(market int, inputMint solana.PublicKey) ([]splitStep, error) {
+	side := obm.takerSide(inputMint)
+	book := obm.Orderbook()
+	baseLotSize := uint64(obm.market.BaseLotSize)
+	quoteLotSize := uint64(obm.market.QuoteLotSize)
+
+	steps := make([]splitStep, 0)
+	iter := book.BookSide(side.InvertSide()).IterAllIncludingInvalid(obm.timestamp, obm.oraclePriceLots)
+	for item, ok := iter.Next(); ok && len(steps) < MAXIMUM_TAKEN_ORDERS; item, ok = iter.Next() {
+		// Invalid orders are dropped by the taker and don't count towards the limit
+		if !item.IsValid() {
+			continue
+		}
+
+		// Only up to half of MaxInt64 native is taken of an order, which
+		// leaves room for the fees and is more than Quote accepts anyway
+		if item.PriceLots > math.MaxInt64/2/obm.market.QuoteLotSize {
+			continue
+		}
+		quantity := min(
+			item.Node.Quantity,
+			math.MaxInt64/2/obm.market.BaseLotSize,
+			math.MaxInt64/2/(item.PriceLots*obm.market.QuoteLotSize),
+		)
+
+		baseNative := uint64(quantity) * baseLotSize
+		quoteNative := uint64(quantity*item.PriceLots) * quoteLotSize
+		takerFees := obm.market.TakerFeesCeil(quoteNative)
+
+		step := splitStep{market: market, lotSize: obm.inputLotSize(inputMint)}
+		switch side {
+		case SideBid:
+			// The fees are rarely a whole number of quote lots
+			step.inAmount = uint64(divCeil(int64(quoteNative+takerFees), int64(quoteLotSize))) * quoteLotSize
+			step.outAmount = baseNative
+		case SideAsk:
+			step.inAmount = baseNative
+			step.outAmount = quoteNative - takerFees
+		}
+		if step.inAmount == 0 || step.outAmount == 0 {
+			continue
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// inputLotSize returns the native amount of one lot of inputMint.
+func (obm *OpenBookMarket) inputLotSize(inputMint solana.PublicKey) uint64 {
+	if obm.takerSide(inputMint) == SideBid {
+		return uint64(obm.market.QuoteLotSize)
+	}
+	return uint64(obm.market.BaseLotSize)
+}
@@ -0,0 +1,125 @@
+package openbookdexgolang
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// newSplitTestMarkets returns two markets with lots of 100 base and 10 quote
+// and a 0.15% taker fee, so that fees are rarely whole quote lots.
+func newSplitTestMarkets(t *testing.T, first, second []BookOrder) []*OpenBookMarket {
+	t.Helper()
+	markets := make([]*OpenBookMarket, 0, 2)
+	for i, orders := range [][]BookOrder{first, second} {
+		market := newTestMarketAccount()
+		market.BaseLotSize = 100
+		market.QuoteLotSize = 10
+		market.TakerFee = 1_500
+		obm := newTestMarket(t, market, orders...)
+		obm.key = solana.PublicKey{0x50, byte(i)}
+		markets = append(markets, obm)
+	}
+	return markets
+}
+
+func TestQuoteSplit(t *testing.T) {
+	// Taking the asks costs 10_000 + 15 and 10_100 + 16 native quote, which
+	// are 10_020 and 10_120 once rounded up to quote lots
+	asks := newSplitTestMarkets(t, []BookOrder{ask(100, 10)}, []BookOrder{ask(101, 10)})
+	bids := newSplitTestMarkets(t, []BookOrder{bid(99, 20)}, []BookOrder{bid(98, 20)})
+
+	tests := []struct {
+		name               string
+		markets            []*OpenBookMarket
+		buy                bool
+		inAmount           uint64
+		allocations        []uint64
+		outAmount          uint64
+		notEnoughLiquidity bool
+	}{
+		{name: "every order", markets: asks, buy: true, inAmount: 20_140, allocations: []uint64{10_020, 10_120}, outAmount: 2_000},
+		{name: "less than a lot left", markets: asks, buy: true, inAmount: 20_149, allocations: []uint64{10_020, 10_120}, outAmount: 2_000},
+		{name: "a lot left", markets: asks, buy: true, inAmount: 20_150, allocations: []uint64{10_020, 10_120}, outAmount: 2_000, notEnoughLiquidity: true},
+		{name: "partial order", markets: asks, buy: true, inAmount: 15_025, allocations: []uint64{10_020, 5_000}, outAmount: 1_400},
+		{name: "partial base lot", markets: bids, inAmount: 2_050, allocations: []uint64{2_000}, outAmount: 19_800},
+		{name: "base beyond the bids", markets: bids, inAmount: 4_100, allocations: []uint64{2_000, 2_000}, outAmount: 19_800 + 19_600, notEnoughLiquidity: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params := &QuoteParams{InAmount: test.inAmount, InputMint: testBaseMint, OutputMint: testQuoteMint}
+			if test.buy {
+				params.InputMint, params.OutputMint = testQuoteMint, testBaseMint
+			}
+
+			split, err := QuoteSplit(test.markets, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(split.Allocations) != len(test.allocations) {
+				t.Fatalf("%d allocations, expected %v", len(split.Allocations), test.allocations)
+			}
+			for i, allocation := range split.Allocations {
+				if allocation.InAmount != test.allocations[i] {
+					t.Fatalf("allocation %d of %d, expected %d", i, allocation.InAmount, test.allocations[i])
+				}
+				if allocation.InAmount%test.markets[i].inputLotSize(params.InputMint) != 0 {
+					t.Fatalf("allocation %d of %d is not in whole lots", i, allocation.InAmount)
+				}
+			}
+			if split.OutAmount != test.outAmount {
+				t.Fatalf("out %d, expected %d", split.OutAmount, test.outAmount)
+			}
+			if split.NotEnoughLiquidity != test.notEnoughLiquidity {
+				t.Fatalf("not enough liquidity %v, expected %v", split.NotEnoughLiquidity, test.notEnoughLiquidity)
+			}
+		})
+	}
+}
+
+func TestQuoteSplitLotSizeLargerThanRemainder(t *testing.T) {
+	// The first market takes quote in lots of 100, the second in lots of 10.
+	// Base costs 10 and 20 on the first market and 30 on the second.
+	first := newTestMarketAccount()
+	first.BaseLotSize = 10
+	first.QuoteLotSize = 100
+	second := newTestMarketAccount()
+	second.QuoteLotSize = 10
+	markets := []*OpenBookMarket{
+		newTestMarket(t, first, ask(1, 10), ask(2, 10)),
+		newTestMarket(t, second, ask(3, 100)),
+	}
+	markets[1].key = solana.PublicKey{0x51}
+
+	// After the first order 50 is left, less than a lot of the first market
+	// but 5 lots of the second
+	split, err := QuoteSplit(markets, &QuoteParams{InAmount: 1_050, InputMint: testQuoteMint, OutputMint: testBaseMint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(split.Allocations) != 2 || split.Allocations[0].InAmount != 1_000 || split.Allocations[1].InAmount != 50 {
+		t.Fatalf("allocations %+v, expected 1000 and 50", split.Allocations)
+	}
+	if split.OutAmount != 101 || split.NotEnoughLiquidity {
+		t.Fatalf("out %d, not enough liquidity %v, expected 101 and false", split.OutAmount, split.NotEnoughLiquidity)
+	}
+}
+
+func TestQuoteSplitHugeOrders(t *testing.T) {
+	// The first order is worth 2^64 + 384 native quote, which used to wrap
+	// around to 384. The second one is priced at nearly MaxInt64 native.
+	markets := newSplitTestMarkets(t, []BookOrder{ask(100, 18_446_744_073_709_552)}, []BookOrder{ask(math.MaxInt64/20, 1)})
+
+	split, err := QuoteSplit(markets, &QuoteParams{InAmount: 1_000_000, InputMint: testQuoteMint, OutputMint: testBaseMint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(split.Allocations) != 1 || split.Allocations[0].InAmount != 1_000_000 {
+		t.Fatalf("allocations %+v, expected all of it on the first market", split.Allocations)
+	}
+	if split.OutAmount != 99_800 || split.NotEnoughLiquidity {
+		t.Fatalf("out %d, not enough liquidity %v", split.OutAmount, split.NotEnoughLiquidity)
+	}
+}
@@ -0,0 +1,144 @@
+package openbookdexgolang
+
+import "github.com/gagliardetto/solana-go"
+
+// TakeOptions are the optional bounds and taker details of a simulated take.
+type TakeOptions struct {
+	// The taker's account and self trade behavior, nil for an unknown taker
+	SelfTrade *SelfTradeConfig
+	// Worst price matched: the highest for bids, the lowest for asks
+	LimitPriceLots *int64
+	// Record every order the take visits in TakeSimulation.Trace
+	Trace bool
+}
+
+// StopReason is why a take stopped matching.
+type StopReason uint8
+
+const (
+	StopBookEmpty      StopReason = iota // Every opposing order was visited
+	StopSizeFilled                       // The base lots were all taken
+	StopQuoteExhausted                   // Too few quote lots are left for the next order
+	StopOrderLimit                       // MAXIMUM_TAKEN_ORDERS orders were matched
+	StopPriceLimit                       // The next order is beyond the limit price
+)
+
+var stopReasonNames = map[StopReason]string{
+	StopBookEmpty:      "book empty",
+	StopSizeFilled:     "size filled",
+	StopQuoteExhausted: "quote exhausted",
+	StopOrderLimit:     "order limit hit",
+	StopPriceLimit:     "price limit",
+}
+
+func (r StopReason) String() string {
+	if name, ok := stopReasonNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func filledStopReason(remainingBaseLots int64) StopReason {
+	if remainingBaseLots == 0 {
+		return StopSizeFilled
+	}
+	return StopQuoteExhausted
+}
+
+// MatchStepKind is what a take did with an order it visited.
+type MatchStepKind uint8
+
+const (
+	MatchStepMatched   MatchStepKind = iota // Matched against the order
+	MatchStepDropped                        // Expired or peg limited, skipped
+	MatchStepSelfTrade                      // Own order, matched without fees
+	MatchStepCancelled                      // Own order, cancelled
+)
+
+var matchStepKindNames = map[MatchStepKind]string{
+	MatchStepMatched:   "matched",
+	MatchStepDropped:   "dropped",
+	MatchStepSelfTrade: "self trade",
+	MatchStepCancelled: "cancelled",
+}
+
+func (k MatchStepKind) String() string {
+	if name, ok := matchStepKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MatchStep is one order visited by a traced take.
+type MatchStep struct {
+	Kind        MatchStepKind
+	Handle      BookSideOrderHandle
+	Key         OrderId
+	Owner       solana.PublicKey
+	PriceLots   int64
+	BaseLots    int64 // Base lots matched
+	QuoteLots   int64 // Quote lots matched
+	MakerRebate int64 // Native quote rebated to the maker
+}
+
+// TakeSimulation is the result of simulating a take against the book.
+type TakeSimulation struct {
+	TotalBaseLotsTaken  int64
+	TotalQuoteLotsTaken int64
+	MakerRebates        int64
+	NotEnoughLiquidity  bool
+	StopReason          StopReason
+	SelfTrades          []SelfTrade
+	Trace               []MatchStep // Only recorded with TakeOptions.Trace
+}
+
+func (t *TakeSimulation) trace(options TakeOptions, kind MatchStepKind, item *BookSideIterItem, baseLots, quoteLots, makerRebate int64) {
+	if !options.Trace {
+		return
+	}
+	t.Trace = append(t.Trace, MatchStep{
+		Kind:        kind,
+		Handle:      item.Handle,
+		Key:         item.Node.OrderId(),
+		Owner:       item.Node.Owner,
+		PriceLots:   item.PriceLots,
+		BaseLots:    baseLots,
+		QuoteLots:   quoteLots,
+		MakerRebate: makerRebate,
+	})
+}
+
+// SimulateTake matches a take against the book like IterateBook, within the
+// bounds of the options.
+//
+// With a SelfTrade config the taker's own resting orders are handled by its
+// self trade behavior, and the simulation fails with ErrWouldSelfTrade when
+// the behavior is AbortTransaction and an own order would be matched. With a
+// limit price matching stops at the first order priced beyond it, like
+// place_take_order.
+func SimulateTake(
+	book Orderbook,
+	side Side,
+	maxBaseLots int64,
+	maxQuoteLots int64,
+	market *Market,
+	oraclePriceLots *int64,
+	nowTs uint64,
+	options TakeOptions,
+) (TakeSimulation, error) {
+	accounts := make([]solana.PublicKey, 0)
+	return iterateBook(book, side, maxBaseLots, maxQuoteLots, market, oraclePriceLots, nowTs, &accounts, options)
+}
+
+// IsPriceWithinLimit reports whether a taker on this side accepts price given
+// its limit price.
+func (s Side) IsPriceWithinLimit(price int64, limit int64) bool {
+	switch s {
+	case Bid:
+		return price <= limit
+	case Ask:
+		return price >= limit
+	default:
+		return false
+	}
+}
@@ -11,3 +11,23 @@ func saturatingAdd(a, b int64) int64 {
 	}
 	return a + b
 }
+
+func saturatingMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		// The sign the product would have had picks the bound
+		if (a < 0) != (b < 0) {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	}
+	return product
+}
+
+// divCeil divides a non-negative a by a positive b, rounding up.
+func divCeil(a, b int64) int64 {
+	return (a + b - 1) / b
+}